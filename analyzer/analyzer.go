@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rrgmc/instruct"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer validates instruct struct tags.
+var Analyzer = NewAnalyzer()
+
+// NewAnalyzer creates a new analyzer with its own flags.
+//
+// Flags:
+//
+//	-tag         struct tag name (default "instruct")
+//	-operations  comma-separated list of known operations. If set, other operations are reported. The
+//	             "-", "recurse" and "recurse_list" operations are always known.
+func NewAnalyzer() *analysis.Analyzer {
+	c := &checker{}
+	ret := &analysis.Analyzer{
+		Name:     "instructtag",
+		Doc:      "check that instruct struct tags are valid",
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+		Run:      c.run,
+	}
+	ret.Flags.StringVar(&c.tagName, "tag", "instruct", "struct tag name")
+	ret.Flags.StringVar(&c.operations, "operations", "", "comma-separated list of known operations")
+	return ret
+}
+
+type checker struct {
+	tagName    string
+	operations string
+}
+
+func (c *checker) run(pass *analysis.Pass) (any, error) {
+	knownOperations := c.knownOperations()
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		for _, field := range n.(*ast.StructType).Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			stag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			tagValue, ok := reflect.StructTag(stag).Lookup(c.tagName)
+			if !ok {
+				continue
+			}
+			c.checkField(pass, field, tagValue, knownOperations)
+		}
+	})
+	return nil, nil
+}
+
+func (c *checker) checkField(pass *analysis.Pass, field *ast.Field, tagValue string, knownOperations map[string]bool) {
+	fieldName := fieldName(field)
+
+	tag, err := instruct.ParseTag(fieldName, tagValue, true, instruct.DefaultFieldNameMapper)
+	if err != nil {
+		pass.Reportf(field.Tag.Pos(), "invalid %s tag on field '%s': %s", c.tagName, fieldName, err)
+		return
+	}
+	if tag.Operation == "" {
+		pass.Reportf(field.Tag.Pos(), "invalid %s tag on field '%s': operation cannot be blank", c.tagName, fieldName)
+		return
+	}
+
+	typ := pass.TypesInfo.TypeOf(field.Type)
+	if typ == nil {
+		return
+	}
+
+	isStructOption := isStructOptionType(typ)
+	if isStructOption && tag.Operation == instruct.OperationIgnore {
+		pass.Reportf(field.Tag.Pos(), "cannot ignore struct option for field '%s'", fieldName)
+		return
+	}
+
+	switch tag.Operation {
+	case instruct.OperationIgnore:
+	case instruct.OperationRecurse:
+		if !isStruct(typ) {
+			pass.Reportf(field.Tag.Pos(), "field '%s' must be a struct to use recurse but is '%s'", fieldName,
+				types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+		}
+	case instruct.OperationRecurseList:
+		slice, ok := typ.Underlying().(*types.Slice)
+		if !ok || !isStruct(slice.Elem()) {
+			pass.Reportf(field.Tag.Pos(), "field '%s' must be a slice of structs to use recurse_list but is '%s'",
+				fieldName, types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+		}
+	default:
+		if knownOperations != nil && !knownOperations[tag.Operation] {
+			pass.Reportf(field.Tag.Pos(), "unknown operation '%s' on field '%s'", tag.Operation, fieldName)
+		}
+	}
+}
+
+// knownOperations returns the set of known operations, or nil if any operation is accepted.
+func (c *checker) knownOperations() map[string]bool {
+	if c.operations == "" {
+		return nil
+	}
+	ret := map[string]bool{
+		instruct.OperationIgnore:      true,
+		instruct.OperationRecurse:     true,
+		instruct.OperationRecurseList: true,
+	}
+	for _, operation := range strings.Split(c.operations, ",") {
+		if operation = strings.TrimSpace(operation); operation != "" {
+			ret[operation] = true
+		}
+	}
+	return ret
+}
+
+// fieldName returns the name of the field, or of the type for embedded fields.
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// isStruct returns whether the type is a struct or a pointer to a struct.
+func isStruct(typ types.Type) bool {
+	for {
+		ptr, ok := typ.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+func isStructOptionType(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "StructOption" && obj.Pkg() != nil && obj.Pkg().Path() == structOptionPkgPath
+}
+
+var structOptionPkgPath = reflect.TypeOf(instruct.StructOption{}).PkgPath()
@@ -0,0 +1,15 @@
+package analyzer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	a := NewAnalyzer()
+	if err := a.Flags.Set("operations", "query,header,body"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, analysistest.TestData(), a, "a")
+}
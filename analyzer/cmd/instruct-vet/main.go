@@ -0,0 +1,20 @@
+// Command instruct-vet validates instruct struct tags, reporting the errors the Decoder would only return on the
+// first decode.
+//
+// Usage:
+//
+//	instruct-vet [-tag=instruct] [-operations=query,header,body] ./...
+//
+// It can also be run by go vet:
+//
+//	go vet -vettool=$(which instruct-vet) ./...
+package main
+
+import (
+	"github.com/rrgmc/instruct/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,14 @@
+// Package analyzer provides a [golang.org/x/tools/go/analysis] analyzer that validates instruct struct tags,
+// reporting at compile time the errors the Decoder would only return on the first decode.
+//
+// It reports:
+//   - tags that can't be parsed, like "required=yes", "so_when=during", unnamed options such as "query,foo" or a
+//     blank operation;
+//   - "recurse" on a field that is not a struct, and "recurse_list" on a field that is not a slice of structs;
+//   - ignored StructOption fields;
+//   - operations that are not in the list of known operations, if one was set.
+//
+// It is a separate module, so importers of instruct don't depend on golang.org/x/tools. The instruct-vet command
+// is in the cmd/instruct-vet directory of this module. The go.work file of this directory builds it with the
+// instruct module of the repository instead of the required version.
+package analyzer
@@ -0,0 +1,13 @@
+module github.com/rrgmc/instruct/analyzer
+
+go 1.22.0
+
+require (
+	github.com/rrgmc/instruct v0.0.0-20261018081222-7869fc0c3ac7
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
@@ -0,0 +1,33 @@
+package a
+
+import "github.com/rrgmc/instruct"
+
+type Inner struct {
+	Value string `instruct:"query"`
+}
+
+type Valid struct {
+	_      instruct.StructOption `instruct:"body,so_when=after,so_recurse=true"`
+	A      string                `instruct:"query,name=a,required=false,default=x"`
+	B      []int                 `instruct:"header,desc=list"`
+	Inner  Inner                 `instruct:"recurse"`
+	PInner *Inner                `instruct:"recurse"`
+	Items  []*Inner              `instruct:"recurse_list"`
+	Ctx    string                `instruct:"-"`
+	Other  string                `json:"other"`
+}
+
+type Invalid struct {
+	_         instruct.StructOption `instruct:"-"`                    // want `cannot ignore struct option for field '_'`
+	When      string                `instruct:"query,so_when=during"` // want `invalid instruct tag on field 'When': invalid 'when' option value: during`
+	Required  string                `instruct:"query,required=yes"`   // want `invalid instruct tag on field 'Required': error parsing 'required' boolean option`
+	Unnamed   string                `instruct:"query,foo"`            // want `invalid instruct tag on field 'Unnamed': unnamed tag option: foo`
+	Missing   string                `instruct:",name=x"`              // want `invalid instruct tag on field 'Missing': operation cannot be blank`
+	Empty     string                `instruct:""`                     // want `invalid instruct tag on field 'Empty': operation cannot be blank`
+	Recurse   string                `instruct:"recurse"`              // want `field 'Recurse' must be a struct to use recurse but is 'string'`
+	List      Inner                 `instruct:"recurse_list"`         // want `field 'List' must be a slice of structs to use recurse_list but is 'Inner'`
+	Unknown   string                `instruct:"qurey"`                // want `unknown operation 'qurey' on field 'Unknown'`
+	Anonymous struct {
+		X int `instruct:"header,so_x=1"` // want `invalid instruct tag on field 'X': unknown struct option name: so_x`
+	} `instruct:"recurse"`
+}
@@ -0,0 +1,3 @@
+package instruct
+
+type StructOption struct{}
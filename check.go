@@ -0,0 +1,119 @@
+package instruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// Check builds the struct info of each type like a decode would, so struct tags, struct options and MapTags
+// usage are validated, and checks that every operation exists in DecodeOperations. Types can be passed as values,
+// pointers or [reflect.Type]. All the types with default MapTags are also checked.
+// All problems are returned at once, using [errors.Join]. The errors of each type are prefixed with its name.
+func (d *Decoder[IT, DC]) Check(data ...any) error {
+	var typs []reflect.Type
+	seen := map[reflect.Type]bool{}
+	addType := func(typ reflect.Type) {
+		if typ != nil {
+			typ = reflectElem(typ)
+		}
+		if !seen[typ] {
+			seen[typ] = true
+			typs = append(typs, typ)
+		}
+	}
+	for _, item := range data {
+		if typ, ok := item.(reflect.Type); ok {
+			addType(typ)
+		} else {
+			addType(reflect.TypeOf(item))
+		}
+	}
+	if d.options.defaultMapTags != nil {
+		mapTagsTypes := d.options.defaultMapTags.Types()
+		sort.Slice(mapTagsTypes, func(i, j int) bool {
+			return mapTagsTypes[i].String() < mapTagsTypes[j].String()
+		})
+		for _, typ := range mapTagsTypes {
+			addType(typ)
+		}
+	}
+
+	var errs []error
+	for _, typ := range typs {
+		si, err := d.structInfoFromType(typ)
+		if err == nil {
+			err = d.checkStructInfo(si)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("type '%v': %w", typ, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Check returns the error from building the struct info of the type, or checks that every operation exists in
+// DecodeOperations. All problems are returned at once, using [errors.Join].
+func (d *TypeDecoder[IT, DC, T]) Check() error {
+	if d.err != nil {
+		return d.err
+	}
+	return d.decoder.checkStructInfo(d.si)
+}
+
+// checkStructInfo checks that every operation used by the structInfo exists in DecodeOperations, and that the
+// elements of "recurse_list" fields have an operation that supports listing names.
+// Returns all problems as [types.DecodeErrors].
+func (d *Decoder[IT, DC]) checkStructInfo(si *structInfo) error {
+	errs := d.checkStructInfoFields(nil, si)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (d *Decoder[IT, DC]) checkStructInfoFields(errs types.DecodeErrors, si *structInfo) types.DecodeErrors {
+	if si.tag != nil && si.tag.IsSO {
+		errs = d.checkOperation(errs, si)
+	}
+	for _, sifield := range si.fields {
+		switch sifield.tag.Operation {
+		case OperationIgnore:
+		case OperationRecurse:
+			errs = d.checkStructInfoFields(errs, sifield)
+		case OperationRecurseList:
+			errs = d.checkStructInfoFields(errs, sifield.elem)
+			if !d.hasNamesOperation(sifield.elem) {
+				errs = append(errs, newFieldError(sifield, nil,
+					errors.New("no operation of the list elements supports listing names")))
+			}
+		default:
+			errs = d.checkOperation(errs, sifield)
+		}
+	}
+	return errs
+}
+
+func (d *Decoder[IT, DC]) checkOperation(errs types.DecodeErrors, si *structInfo) types.DecodeErrors {
+	if _, ok := d.options.DecodeOperations[si.tag.Operation]; !ok {
+		errs = append(errs, newFieldError(si, nil,
+			fmt.Errorf("%w '%s'", types.ErrUnknownOperation, si.tag.Operation)))
+	}
+	return errs
+}
+
+// hasNamesOperation returns whether any of the known operations used by the structInfo implements
+// [DecodeOperationNames].
+func (d *Decoder[IT, DC]) hasNamesOperation(si *structInfo) bool {
+	operations := map[string]bool{}
+	si.collectOperations(operations)
+	for opname := range operations {
+		if _, ok := d.options.DecodeOperations[opname].(DecodeOperationNames[IT, DC]); ok {
+			return true
+		}
+	}
+	return false
+}
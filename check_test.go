@@ -0,0 +1,115 @@
+package instruct
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/rrgmc/instruct/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoderCheck(t *testing.T) {
+	type Inner struct {
+		X int `instruct:"cookie"`
+	}
+
+	type Item struct {
+		Name string `instruct:"header"`
+	}
+
+	type Valid struct {
+		A     string `instruct:"query"`
+		Inner struct {
+			B int `instruct:"header"`
+		} `instruct:"recurse"`
+	}
+
+	type Invalid struct {
+		_     StructOption `instruct:"session,so_recurse=true"`
+		A     string       `instruct:"query"`
+		B     string       `instruct:"path"`
+		Inner Inner        `instruct:"recurse"`
+		Items []Item       `instruct:"recurse_list"`
+	}
+
+	type InvalidTag struct {
+		A string `instruct:"query,required=yes"`
+	}
+
+	type InvalidMapTags struct {
+		A string
+	}
+
+	require.NoError(t, NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions()).
+		Check(reflect.TypeOf(Valid{})))
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	dec.options.DefaultMapTagsSet(reflect.TypeOf(InvalidMapTags{}), MapTags{
+		"A": "query",
+		"Z": "query",
+	})
+
+	err := dec.Check(&Valid{}, Invalid{}, reflect.TypeOf(&InvalidTag{}))
+	require.Error(t, err)
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+	errs := joined.Unwrap()
+	require.Len(t, errs, 3)
+
+	var derrs types.DecodeErrors
+	require.ErrorAs(t, errs[0], &derrs)
+	require.Len(t, derrs, 4)
+	for _, ferr := range derrs[:3] {
+		require.ErrorIs(t, ferr, types.ErrUnknownOperation)
+	}
+	require.Empty(t, derrs[0].FieldPath)
+	require.Equal(t, "session", derrs[0].Operation)
+	require.Equal(t, []string{"B"}, derrs[1].FieldPath)
+	require.Equal(t, []string{"Inner", "X"}, derrs[2].FieldPath)
+	require.Equal(t, []string{"Items"}, derrs[3].FieldPath)
+	require.ErrorContains(t, derrs[3], "no operation of the list elements supports listing names")
+
+	require.ErrorContains(t, errs[1], "type 'instruct.InvalidTag'")
+	require.ErrorContains(t, errs[1], "error parsing 'required' boolean option")
+	require.ErrorContains(t, errs[2], "type 'instruct.InvalidMapTags'")
+}
+
+func TestTypeDecoderCheck(t *testing.T) {
+	type Valid struct {
+		A string `instruct:"query"`
+	}
+
+	type Invalid struct {
+		A string `instruct:"query"`
+		B string `instruct:"path"`
+		C string `instruct:"form"`
+	}
+
+	require.NoError(t, NewTypeDecoder[*http.Request, TestDecodeContext, Valid](GetTestTypeDecoderOptions()).Check())
+
+	err := NewTypeDecoder[*http.Request, TestDecodeContext, Invalid](GetTestTypeDecoderOptions()).Check()
+	var derrs types.DecodeErrors
+	require.True(t, errors.As(err, &derrs))
+	require.Len(t, derrs, 2)
+	require.ErrorIs(t, derrs[0], types.ErrUnknownOperation)
+	require.ErrorIs(t, derrs[1], types.ErrUnknownOperation)
+}
+
+func TestDecoderCheckFieldErrors(t *testing.T) {
+	type Invalid struct {
+		A string `instruct:"query,required=yes"`
+		B int    `instruct:"recurse"`
+		C string `instruct:"query"`
+		D []int  `instruct:"recurse_list"`
+	}
+
+	err := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions()).Check(Invalid{})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "type 'instruct.Invalid'")
+	require.ErrorContains(t, err, "error on field 'A': error parsing 'required' boolean option")
+	require.ErrorContains(t, err, "field 'B' must be a struct to use recurse but is 'int'")
+	require.ErrorContains(t, err, "field 'D' must be a slice of structs to use recurse_list but is '[]int'")
+}
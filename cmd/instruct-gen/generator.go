@@ -0,0 +1,532 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rrgmc/instruct"
+)
+
+type options struct {
+	tagName         string
+	defaultRequired bool
+	args            []string // command line arguments, written to the generated file header.
+}
+
+// coerceFuncs maps the primitive types to the coerce function, the same one used by the default resolver.
+var coerceFuncs = map[string]string{
+	"bool":    "Bool",
+	"string":  "String",
+	"int":     "Int",
+	"int8":    "Int8",
+	"int16":   "Int16",
+	"int32":   "Int32",
+	"rune":    "Int32",
+	"int64":   "Int64",
+	"uint":    "Uint",
+	"uint8":   "Uint8",
+	"byte":    "Uint8",
+	"uint16":  "Uint16",
+	"uint32":  "Uint32",
+	"uint64":  "Uint64",
+	"float32": "Float32",
+	"float64": "Float64",
+}
+
+// fallbackError signals that the type is not supported by generated code, and the reflective decoder must be
+// used.
+type fallbackError struct {
+	reason string
+}
+
+func newFallbackError(format string, args ...any) error {
+	return &fallbackError{reason: fmt.Sprintf(format, args...)}
+}
+
+func (e *fallbackError) Error() string {
+	return e.reason
+}
+
+// generate parses the package in dir and returns the generated source for the types.
+func generate(dir string, typeNames []string, opt options) ([]byte, error) {
+	// only the files matching the build constraints, like "x_linux.go" or "//go:build ignore", are used.
+	bpkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := map[string]*ast.File{}
+	for _, fileName := range append(append([]string{}, bpkg.GoFiles...), bpkg.CgoFiles...) {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files[fileName] = file
+	}
+
+	g := &generator{
+		opt:     opt,
+		structs: map[string]*ast.StructType{},
+		imports: map[string]bool{},
+	}
+
+	// sort file names to have a stable output.
+	var fileNames []string
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		ast.Inspect(files[fileName], func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok && ts.TypeParams == nil {
+					g.structs[ts.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	for _, typeName := range typeNames {
+		if err := g.generateType(strings.TrimSpace(typeName)); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.source(bpkg.Name)
+}
+
+type generator struct {
+	opt     options
+	structs map[string]*ast.StructType
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+// source returns the formatted source of the generated file.
+func (g *generator) source(pkgName string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by \"instruct-gen %s\"; DO NOT EDIT.\n\n", strings.Join(g.opt.args, " "))
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+
+	// standard library imports first.
+	var stdImports, imports []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			imports = append(imports, imp)
+		} else {
+			stdImports = append(stdImports, imp)
+		}
+	}
+	sort.Strings(stdImports)
+	sort.Strings(imports)
+	buf.WriteString("import (\n")
+	for _, imp := range stdImports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	if len(stdImports) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, imp := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n")
+
+	buf.Write(g.body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated source: %w", err)
+	}
+	return src, nil
+}
+
+// generateType generates the decode function of a type. If the type is not supported, a function calling the
+// reflective decoder is generated.
+func (g *generator) generateType(typeName string) error {
+	st, ok := g.structs[typeName]
+	if !ok {
+		return fmt.Errorf("struct type '%s' not found", typeName)
+	}
+
+	tg := &typeGenerator{
+		g:        g,
+		typeName: typeName,
+		imports:  map[string]bool{},
+	}
+	err := tg.generateStruct(st, "data", nil, true, []string{typeName})
+	var ferr *fallbackError
+	if errors.As(err, &ferr) {
+		g.generateFallback(typeName, ferr)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error generating type '%s': %w", typeName, err)
+	}
+
+	for imp := range tg.imports {
+		g.imports[imp] = true
+	}
+	g.imports["reflect"] = true
+	g.imports["github.com/rrgmc/instruct"] = true
+
+	tagsVar := lowerFirst(typeName) + "InstructTags"
+
+	fmt.Fprintf(&g.body, "\nvar %s = [...]*instruct.Tag{\n", tagsVar)
+	for _, tag := range tg.tags {
+		fmt.Fprintf(&g.body, "\tinstruct.MustParseTag(%q, %q, %t, instruct.DefaultFieldNameMapper),\n",
+			tag.fieldName, tag.tagValue, g.opt.defaultRequired)
+	}
+	g.body.WriteString("}\n")
+
+	fmt.Fprintf(&g.body, `
+// Decode%[1]s decodes the input to the %[1]s struct calling the decode operations of the Decoder directly.
+// It falls back to [instruct.Decoder.Decode] if the decoder options are not supported by generated code.
+func Decode%[1]s[IT any, DC instruct.DecodeContext](dec *instruct.Decoder[IT, DC], input IT, data *%[1]s,
+	decodeOptions instruct.DecodeOptions[IT, DC]) error {
+	if data == nil {
+		return dec.Decode(input, data, decodeOptions)
+	}
+	gd, ok := dec.Generated(reflect.TypeOf(data), %[2]q, %[3]t, decodeOptions)
+	if !ok {
+		return dec.Decode(input, data, decodeOptions)
+	}
+`, typeName, g.opt.tagName, g.opt.defaultRequired)
+	g.body.WriteString(strings.ReplaceAll(tg.body.String(), "%TAGS%", tagsVar))
+	g.body.WriteString("\treturn gd.Validate(input)\n}\n")
+	return nil
+}
+
+// generateFallback generates a decode function that always uses the reflective decoder.
+func (g *generator) generateFallback(typeName string, reason *fallbackError) {
+	g.imports["github.com/rrgmc/instruct"] = true
+
+	fmt.Fprintf(&g.body, `
+// Decode%[1]s decodes the input to the %[1]s struct using [instruct.Decoder.Decode].
+// Generated code is not used because %[2]s.
+func Decode%[1]s[IT any, DC instruct.DecodeContext](dec *instruct.Decoder[IT, DC], input IT, data *%[1]s,
+	decodeOptions instruct.DecodeOptions[IT, DC]) error {
+	return dec.Decode(input, data, decodeOptions)
+}
+`, typeName, reason.reason)
+}
+
+// typeGenerator generates the body of the decode function of a type, with all inner structs inlined.
+type typeGenerator struct {
+	g        *generator
+	typeName string
+	imports  map[string]bool
+	tags     []genTag
+	levels   int
+	body     bytes.Buffer
+}
+
+type genTag struct {
+	fieldName string
+	tagValue  string
+}
+
+// genField is a parsed struct field.
+type genField struct {
+	name      string
+	anonymous bool
+	typ       ast.Expr
+	tagValue  string
+	tag       *instruct.Tag
+}
+
+// parseFields parses the exported fields of the struct, in the same way the struct info builder does.
+func (t *typeGenerator) parseFields(st *ast.StructType) ([]genField, error) {
+	var ret []genField
+	for _, field := range st.Fields.List {
+		if isStructOptionType(field.Type) {
+			return nil, newFallbackError("struct options are not supported")
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		anonymous := len(names) == 0
+		if anonymous {
+			name, ok := typeIdentName(field.Type)
+			if !ok {
+				return nil, newFallbackError("unsupported embedded field type")
+			}
+			names = append(names, name)
+		}
+
+		var tagValue string
+		hasTag := false
+		if field.Tag != nil {
+			structTag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tagValue, hasTag = reflect.StructTag(structTag).Lookup(t.g.opt.tagName)
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			fieldTagValue := tagValue
+			if !hasTag {
+				if !anonymous {
+					return nil, newFallbackError("field '%s' has no tag", name)
+				}
+				fieldTagValue = instruct.OperationRecurse
+			}
+			tag, err := instruct.ParseTag(name, fieldTagValue, t.g.opt.defaultRequired, instruct.DefaultFieldNameMapper)
+			if err != nil {
+				return nil, fmt.Errorf("error on field '%s': %w", name, err)
+			}
+			ret = append(ret, genField{
+				name:      name,
+				anonymous: anonymous,
+				typ:       field.Type,
+				tagValue:  fieldTagValue,
+				tag:       tag,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// generateStruct generates the decoding of the fields of a struct. "expr" is the Go expression to access the
+// struct, and "path" is the field path used in errors.
+func (t *typeGenerator) generateStruct(st *ast.StructType, expr string, path []string, isPointer bool,
+	typeStack []string) error {
+	fields, err := t.parseFields(st)
+	if err != nil {
+		return err
+	}
+
+	// only declare the defaults variable if it is used by a required field.
+	hasRequired := false
+	for _, field := range fields {
+		if field.tag.Required && field.tag.Operation != instruct.OperationIgnore &&
+			field.tag.Operation != instruct.OperationRecurse {
+			hasRequired = true
+		}
+	}
+
+	addrExpr := expr
+	if !isPointer {
+		addrExpr = "&" + expr
+	}
+	defaultsVar := fmt.Sprintf("structDefaults%d", t.levels)
+	t.levels++
+	if hasRequired {
+		fmt.Fprintf(&t.body, "\t%s := gd.StructDefaults(%s)\n", defaultsVar, addrExpr)
+	} else {
+		fmt.Fprintf(&t.body, "\tgd.StructDefaults(%s)\n", addrExpr)
+	}
+
+	for _, field := range fields {
+		fieldExpr := expr + "." + field.name
+		fieldPath := append(append([]string{}, path...), field.name)
+		tagExpr := t.addTag(field.name, field.tagValue)
+
+		fmt.Fprintf(&t.body, `	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError(%#v, %s, nil, err)
+	}
+`, fieldPath, tagExpr)
+
+		switch field.tag.Operation {
+		case instruct.OperationIgnore:
+		case instruct.OperationRecurse:
+			typeName, isFieldPointer, ok := t.localStructType(field.typ)
+			if !ok {
+				return newFallbackError("field '%s' must be a struct of the same package to use recurse", field.name)
+			}
+			for _, stackName := range typeStack {
+				if stackName == typeName {
+					return newFallbackError("recursive type '%s'", typeName)
+				}
+			}
+			if isFieldPointer {
+				fmt.Fprintf(&t.body, "\tif %s == nil {\n\t\t%s = new(%s)\n\t}\n", fieldExpr, fieldExpr, typeName)
+			}
+			innerPath := path
+			if !field.anonymous {
+				innerPath = fieldPath
+			}
+			if err := t.generateStruct(t.g.structs[typeName], fieldExpr, innerPath, isFieldPointer,
+				append(typeStack, typeName)); err != nil {
+				return err
+			}
+		case instruct.OperationRecurseList:
+			return newFallbackError("field '%s' uses recurse_list", field.name)
+		default:
+			t.generateOperation(field, fieldExpr, fieldPath, tagExpr, defaultsVar)
+		}
+	}
+	return nil
+}
+
+// generateOperation generates the call to the decode operation of a field and the conversion of its value.
+func (t *typeGenerator) generateOperation(field genField, fieldExpr string, fieldPath []string, tagExpr string,
+	defaultsVar string) {
+	pathExpr := fmt.Sprintf("%#v", fieldPath)
+
+	// only unnamed slices/arrays/maps are lists, the same check of the reflective decoder.
+	isList := false
+	switch field.typ.(type) {
+	case *ast.ArrayType, *ast.MapType:
+		isList = true
+	}
+
+	fmt.Fprintf(&t.body, `	{
+		fieldValue := reflect.ValueOf(&%s).Elem()
+		dataWasSet, value, err := gd.Decode(input, %t, fieldValue, %s)
+		if err != nil {
+			return gd.FieldError(%s, %s, nil, err)
+		}
+`, fieldExpr, isList, tagExpr, pathExpr, tagExpr)
+
+	if field.tag.HasDefault {
+		var defaultValue string
+		if isList {
+			if field.tag.Default == "" {
+				defaultValue = "[]string{}"
+			} else {
+				defaultValue = fmt.Sprintf("%#v", strings.Split(field.tag.Default, ","))
+			}
+		} else {
+			defaultValue = strconv.Quote(field.tag.Default)
+		}
+		fmt.Fprintf(&t.body, "\t\tif !dataWasSet {\n\t\t\tdataWasSet, value = true, %s\n\t\t}\n", defaultValue)
+	}
+
+	t.body.WriteString("\t\tif dataWasSet && value != instruct.IgnoreDecodeValue {\n")
+	if coerceFunc, ok := primitiveCoerceFunc(field.typ); ok {
+		// primitive type
+		t.imports["github.com/rrgmc/instruct/coerce"] = true
+		t.imports["github.com/rrgmc/instruct/types"] = true
+		fmt.Fprintf(&t.body, `			c, cerr := coerce.%s(value)
+			%s = c
+			if cerr != nil {
+				return gd.FieldError(%s, %s, value, types.NewCoerceError(cerr))
+			}
+`, coerceFunc, fieldExpr, pathExpr, tagExpr)
+	} else if at, ok := field.typ.(*ast.ArrayType); ok && at.Len == nil && isPrimitive(at.Elt) {
+		// slice of primitive type, the value must be a []string, otherwise the resolver is used.
+		coerceFunc, _ := primitiveCoerceFunc(at.Elt)
+		t.imports["github.com/rrgmc/instruct/coerce"] = true
+		t.imports["github.com/rrgmc/instruct/types"] = true
+		fmt.Fprintf(&t.body, `			if items, ok := value.([]string); ok {
+				list := make([]%s, 0, len(items))
+				for _, item := range items {
+					c, cerr := coerce.%s(item)
+					if cerr != nil {
+						return gd.FieldError(%s, %s, value, types.NewCoerceError(cerr))
+					}
+					list = append(list, c)
+				}
+				%s = list
+			} else if err = gd.Resolve(fieldValue, value, %s); err != nil {
+				return gd.FieldError(%s, %s, value, err)
+			}
+`, at.Elt.(*ast.Ident).Name, coerceFunc, pathExpr, tagExpr, fieldExpr, tagExpr, pathExpr, tagExpr)
+	} else {
+		// any other type uses the resolver.
+		fmt.Fprintf(&t.body, `			if err = gd.Resolve(fieldValue, value, %s); err != nil {
+				return gd.FieldError(%s, %s, value, err)
+			}
+`, tagExpr, pathExpr, tagExpr)
+	}
+	t.body.WriteString("\t\t}\n")
+
+	if field.tag.Required {
+		fmt.Fprintf(&t.body, `		if !dataWasSet && %s.FieldSet(%q, fieldValue) {
+			dataWasSet = true
+		}
+		if !dataWasSet {
+			return gd.RequiredError(%s, %s)
+		}
+`, defaultsVar, field.name, pathExpr, tagExpr)
+	}
+	t.body.WriteString("\t}\n")
+}
+
+// addTag adds a tag to be parsed at initialization, and returns the expression to access it.
+func (t *typeGenerator) addTag(fieldName string, tagValue string) string {
+	t.tags = append(t.tags, genTag{
+		fieldName: fieldName,
+		tagValue:  tagValue,
+	})
+	return fmt.Sprintf("%%TAGS%%[%d]", len(t.tags)-1)
+}
+
+// localStructType returns the name of the struct type of the expression, if it is a struct of the same
+// package, or a pointer to one.
+func (t *typeGenerator) localStructType(expr ast.Expr) (string, bool, bool) {
+	isPointer := false
+	if se, ok := expr.(*ast.StarExpr); ok {
+		isPointer = true
+		expr = se.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false, false
+	}
+	if _, ok := t.g.structs[ident.Name]; !ok {
+		return "", false, false
+	}
+	return ident.Name, isPointer, true
+}
+
+func isPrimitive(expr ast.Expr) bool {
+	_, ok := primitiveCoerceFunc(expr)
+	return ok
+}
+
+func primitiveCoerceFunc(expr ast.Expr) (string, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	f, ok := coerceFuncs[ident.Name]
+	return f, ok
+}
+
+// isStructOptionType returns whether the type is the instruct.StructOption type.
+func isStructOptionType(expr ast.Expr) bool {
+	if se, ok := expr.(*ast.SelectorExpr); ok {
+		return se.Sel.Name == "StructOption"
+	}
+	return false
+}
+
+// typeIdentName returns the name of an embedded field type.
+func typeIdentName(expr ast.Expr) (string, bool) {
+	switch xexpr := expr.(type) {
+	case *ast.Ident:
+		return xexpr.Name, true
+	case *ast.StarExpr:
+		return typeIdentName(xexpr.X)
+	case *ast.SelectorExpr:
+		return xexpr.Sel.Name, true
+	}
+	return "", false
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
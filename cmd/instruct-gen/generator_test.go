@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "gentest")
+
+	src, err := generate(dir, []string{"User", "Item"}, options{
+		tagName:         "instruct",
+		defaultRequired: true,
+		args:            []string{"-type=User,Item"},
+	})
+	require.NoError(t, err)
+
+	current, err := os.ReadFile(filepath.Join(dir, "user_instruct.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(current), string(src), "generated code is outdated, run 'go generate'")
+}
+
+func TestGenerateTypeNotFound(t *testing.T) {
+	_, err := generate(filepath.Join("internal", "gentest"), []string{"Unknown"}, options{
+		tagName:         "instruct",
+		defaultRequired: true,
+	})
+	require.Error(t, err)
+}
+
+func TestGenerateBuildConstraints(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"req.go": "package p\n\ntype Req struct {\n\tName string `instruct:\"query\"`\n}\n",
+		// the same type in files excluded by build constraints must not be used.
+		"req_ignore.go": "//go:build ignore\n\npackage p\n\ntype Req struct {\n\tIgnored string `instruct:\"query\"`\n}\n",
+		"req_plan9.go":  "package p\n\ntype Req struct {\n\tPlan9 string `instruct:\"query\"`\n}\n",
+		"req_test.go":   "package p\n\ntype Req struct {\n\tTest string `instruct:\"query\"`\n}\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	src, err := generate(dir, []string{"Req"}, options{
+		tagName:         "instruct",
+		defaultRequired: true,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(src), "data.Name")
+	require.NotContains(t, string(src), "Ignored")
+	require.NotContains(t, string(src), "Plan9")
+	require.NotContains(t, string(src), "data.Test")
+}
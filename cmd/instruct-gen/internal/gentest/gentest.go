@@ -0,0 +1,47 @@
+// Package gentest contains types used to test the code generated by instruct-gen.
+package gentest
+
+import (
+	"time"
+
+	"github.com/rrgmc/instruct"
+)
+
+//go:generate go run github.com/rrgmc/instruct/cmd/instruct-gen -type=User,Item
+
+type Address struct {
+	City    string `instruct:"query,name=city"`
+	Country string `instruct:"header,required=false,default=BR"`
+}
+
+type Audit struct {
+	Source string `instruct:"header,name=X-Source,required=false"`
+}
+
+type User struct {
+	Audit
+	ID       int64          `instruct:"header,name=X-User-ID"`
+	Name     string         `instruct:"query"`
+	Nick     string         `instruct:"query,required=false,trim=true"`
+	Active   bool           `instruct:"query,required=false"`
+	Score    float64        `instruct:"query,required=false"`
+	Level    uint8          `instruct:"query,required=false,default=3"`
+	Tags     []string       `instruct:"query,required=false"`
+	IDs      []int          `instruct:"query,required=false,default=5"`
+	Since    time.Time      `instruct:"query,required=false"`
+	Labels   map[string]int `instruct:"query,required=false"`
+	Address  Address        `instruct:"recurse"`
+	Billing  *Address       `instruct:"recurse,required=false"`
+	Internal string         `instruct:"-"`
+}
+
+// Defaults implements instruct.StructDefaults.
+func (u *User) Defaults() {
+	u.Name = "anonymous"
+}
+
+// Item uses a struct option, so the generated code always uses the reflective decoder.
+type Item struct {
+	_    instruct.StructOption `instruct:"header,name=X-Item,required=false"`
+	Name string                `instruct:"query"`
+}
@@ -0,0 +1,276 @@
+package gentest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/resolver"
+	"github.com/rrgmc/instruct/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testDecodeOperationQuery struct{}
+
+func (d *testDecodeOperationQuery) Decode(ctx instruct.DecodeContext, r *http.Request, isList bool, field reflect.Value, tag *instruct.Tag) (bool, any, error) {
+	if !r.URL.Query().Has(tag.Name) {
+		return false, nil, nil
+	}
+	if isList {
+		return true, r.URL.Query()[tag.Name], nil
+	}
+	return true, r.URL.Query().Get(tag.Name), nil
+}
+
+type testDecodeOperationHeader struct{}
+
+func (d *testDecodeOperationHeader) Decode(ctx instruct.DecodeContext, r *http.Request, isList bool, field reflect.Value, tag *instruct.Tag) (bool, any, error) {
+	if _, ok := r.Header[http.CanonicalHeaderKey(tag.Name)]; !ok {
+		return false, nil, nil
+	}
+	if isList {
+		return true, r.Header.Values(tag.Name), nil
+	}
+	return true, r.Header.Get(tag.Name), nil
+}
+
+// testDecodeOperationQuerySpec is a query operation that declares its tag options.
+type testDecodeOperationQuerySpec struct {
+	testDecodeOperationQuery
+	spec []types.TagOptionSpec
+}
+
+func (d *testDecodeOperationQuerySpec) OptionsSpec() []types.TagOptionSpec {
+	return d.spec
+}
+
+func newTestDecoder() *instruct.Decoder[*http.Request, instruct.DecodeContext] {
+	optns := instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]()
+	optns.DecodeOperations["query"] = &testDecodeOperationQuery{}
+	optns.DecodeOperations["header"] = &testDecodeOperationHeader{}
+	return instruct.NewDecoder[*http.Request, instruct.DecodeContext](optns)
+}
+
+func newTestDecodeOptions(ctx context.Context) instruct.DecodeOptions[*http.Request, instruct.DecodeContext] {
+	dc := instruct.NewDefaultDecodeContext(instruct.DefaultFieldNameMapper)
+	if ctx != nil {
+		dc.SetContext(ctx)
+	}
+	optns := instruct.NewDecodeOptions[*http.Request, instruct.DecodeContext]()
+	optns.Ctx = &dc
+	return optns
+}
+
+func newTestRequest(query url.Values, header map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestGeneratedEqualsReflective(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		query  url.Values
+		header map[string]string
+		ctx    context.Context
+		data   User // initial value.
+	}{
+		{
+			name: "all fields",
+			query: url.Values{
+				"name":   {"john"},
+				"active": {"true"},
+				"score":  {"9.5"},
+				"level":  {"7"},
+				"tags":   {"a", "b"},
+				"ids":    {"1", "2", "3"},
+				"since":  {time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC).Format(time.RFC3339)},
+				"labels": {"x=1,y=2"},
+				"city":   {"rio"},
+			},
+			header: map[string]string{
+				"X-User-ID": "12",
+				"X-Source":  "web",
+				"Country":   "US",
+			},
+		},
+		{
+			name:   "defaults",
+			query:  url.Values{"city": {"rio"}},
+			header: map[string]string{"X-User-ID": "12"},
+		},
+		{
+			name:  "required error",
+			query: url.Values{"city": {"rio"}},
+		},
+		{
+			// only values set by Defaults count as set for required fields.
+			name:  "prefilled required error",
+			query: url.Values{"city": {"rio"}},
+			data:  User{ID: 5},
+		},
+		{
+			name:   "coerce error",
+			query:  url.Values{"city": {"rio"}},
+			header: map[string]string{"X-User-ID": "abc"},
+		},
+		{
+			name:   "slice coerce error",
+			query:  url.Values{"city": {"rio"}, "ids": {"1", "x"}},
+			header: map[string]string{"X-User-ID": "12"},
+		},
+		{
+			name:   "resolver error",
+			query:  url.Values{"city": {"rio"}, "labels": {"invalid"}},
+			header: map[string]string{"X-User-ID": "12"},
+		},
+		{
+			name:   "context canceled",
+			query:  url.Values{"city": {"rio"}},
+			header: map[string]string{"X-User-ID": "12"},
+			ctx:    canceled,
+		},
+	}
+
+	dec := newTestDecoder()
+
+	// ensure the generated code is used
+	_, ok := dec.Generated(reflect.TypeOf(&User{}), "instruct", true, newTestDecodeOptions(nil))
+	require.True(t, ok)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reflective, generated := tt.data, tt.data
+			reflectiveErr := dec.Decode(newTestRequest(tt.query, tt.header), &reflective, newTestDecodeOptions(tt.ctx))
+			generatedErr := DecodeUser(dec, newTestRequest(tt.query, tt.header), &generated, newTestDecodeOptions(tt.ctx))
+			require.Equal(t, reflectiveErr, generatedErr)
+			require.Equal(t, reflective, generated)
+		})
+	}
+}
+
+func TestGeneratedFallback(t *testing.T) {
+	r := newTestRequest(url.Values{"nm": {"john"}, "city": {"rio"}}, map[string]string{"X-User-ID": "12"})
+
+	dec := newTestDecoder()
+
+	var reflective, generated User
+	decodeOptions := newTestDecodeOptions(nil)
+	decodeOptions.MapTags = instruct.MapTags{
+		"Name": "query,name=nm",
+	}
+	require.NoError(t, dec.Decode(r, &reflective, decodeOptions))
+	require.NoError(t, DecodeUser(dec, r, &generated, decodeOptions))
+	require.Equal(t, "john", generated.Name)
+	require.Equal(t, reflective, generated)
+}
+
+func TestGeneratedStructOption(t *testing.T) {
+	r := newTestRequest(url.Values{"name": {"i1"}}, nil)
+
+	dec := newTestDecoder()
+
+	var reflective, generated Item
+	require.NoError(t, dec.Decode(r, &reflective, newTestDecodeOptions(nil)))
+	require.NoError(t, DecodeItem(dec, r, &generated, newTestDecodeOptions(nil)))
+	require.Equal(t, reflective, generated)
+}
+
+func TestGeneratedOptionsSpec(t *testing.T) {
+	r := newTestRequest(url.Values{"name": {"john"}, "nick": {"jj"}, "city": {"rio"}},
+		map[string]string{"X-User-ID": "12"})
+
+	tests := []struct {
+		name      string
+		spec      []types.TagOptionSpec
+		generated bool
+		err       string
+	}{
+		{
+			name:      "valid options",
+			spec:      []types.TagOptionSpec{{Name: "trim", Type: types.TagOptionBool}},
+			generated: true,
+		},
+		{
+			name: "unknown option",
+			err:  "unknown option 'trim' for operation 'query'",
+		},
+		{
+			name: "invalid option value",
+			spec: []types.TagOptionSpec{{Name: "trim", Type: types.TagOptionEnum, Values: []string{"left", "right"}}},
+			err:  "invalid value 'true' for option 'trim', must be one of: left, right",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optns := instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]()
+			optns.DecodeOperations["query"] = &testDecodeOperationQuerySpec{spec: tt.spec}
+			optns.DecodeOperations["header"] = &testDecodeOperationHeader{}
+			dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](optns)
+
+			_, ok := dec.Generated(reflect.TypeOf(&User{}), "instruct", true, newTestDecodeOptions(nil))
+			require.Equal(t, tt.generated, ok)
+
+			var reflective, generated User
+			reflectiveErr := dec.Decode(r, &reflective, newTestDecodeOptions(nil))
+			generatedErr := DecodeUser(dec, r, &generated, newTestDecodeOptions(nil))
+			require.Equal(t, reflectiveErr, generatedErr)
+			require.Equal(t, reflective, generated)
+			if tt.err != "" {
+				require.ErrorContains(t, generatedErr, tt.err)
+			} else {
+				require.NoError(t, generatedErr)
+				require.Equal(t, "jj", generated.Nick)
+			}
+		})
+	}
+}
+
+// testUpperString resolves strings in upper case.
+type testUpperString struct{}
+
+func (t testUpperString) ResolveTypeValue(target reflect.Value, value any) error {
+	if target.Kind() != reflect.String {
+		return types.ErrCoerceUnknown
+	}
+	s, ok := value.(string)
+	if !ok {
+		return types.ErrCoerceUnknown
+	}
+	target.SetString(strings.ToUpper(s))
+	return nil
+}
+
+func TestGeneratedCustomTypes(t *testing.T) {
+	r := newTestRequest(url.Values{"name": {"john"}, "city": {"rio"}, "tags": {"a", "b"}},
+		map[string]string{"X-User-ID": "12"})
+
+	optns := instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]()
+	optns.DecodeOperations["query"] = &testDecodeOperationQuery{}
+	optns.DecodeOperations["header"] = &testDecodeOperationHeader{}
+	optns.Resolver = resolver.NewResolver(resolver.WithValueResolver(
+		resolver.NewDefaultValueResolver(resolver.WithCustomType(testUpperString{}))))
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](optns)
+
+	_, ok := dec.Generated(reflect.TypeOf(User{}), "instruct", true, newTestDecodeOptions(nil))
+	require.False(t, ok)
+
+	var reflective, generated User
+	require.NoError(t, dec.Decode(r, &reflective, newTestDecodeOptions(nil)))
+	require.NoError(t, DecodeUser(dec, r, &generated, newTestDecodeOptions(nil)))
+	require.Equal(t, "JOHN", generated.Name)
+	require.Equal(t, []string{"A", "B"}, generated.Tags)
+	require.Equal(t, reflective, generated)
+}
@@ -0,0 +1,376 @@
+// Code generated by "instruct-gen -type=User,Item"; DO NOT EDIT.
+
+package gentest
+
+import (
+	"reflect"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/coerce"
+	"github.com/rrgmc/instruct/types"
+)
+
+var userInstructTags = [...]*instruct.Tag{
+	instruct.MustParseTag("Audit", "recurse", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Source", "header,name=X-Source,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("ID", "header,name=X-User-ID", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Name", "query", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Nick", "query,required=false,trim=true", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Active", "query,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Score", "query,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Level", "query,required=false,default=3", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Tags", "query,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("IDs", "query,required=false,default=5", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Since", "query,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Labels", "query,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Address", "recurse", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("City", "query,name=city", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Country", "header,required=false,default=BR", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Billing", "recurse,required=false", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("City", "query,name=city", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Country", "header,required=false,default=BR", true, instruct.DefaultFieldNameMapper),
+	instruct.MustParseTag("Internal", "-", true, instruct.DefaultFieldNameMapper),
+}
+
+// DecodeUser decodes the input to the User struct calling the decode operations of the Decoder directly.
+// It falls back to [instruct.Decoder.Decode] if the decoder options are not supported by generated code.
+func DecodeUser[IT any, DC instruct.DecodeContext](dec *instruct.Decoder[IT, DC], input IT, data *User,
+	decodeOptions instruct.DecodeOptions[IT, DC]) error {
+	if data == nil {
+		return dec.Decode(input, data, decodeOptions)
+	}
+	gd, ok := dec.Generated(reflect.TypeOf(data), "instruct", true, decodeOptions)
+	if !ok {
+		return dec.Decode(input, data, decodeOptions)
+	}
+	structDefaults0 := gd.StructDefaults(data)
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Audit"}, userInstructTags[0], nil, err)
+	}
+	gd.StructDefaults(&data.Audit)
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Source"}, userInstructTags[1], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Audit.Source).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[1])
+		if err != nil {
+			return gd.FieldError([]string{"Source"}, userInstructTags[1], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Audit.Source = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Source"}, userInstructTags[1], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"ID"}, userInstructTags[2], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.ID).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[2])
+		if err != nil {
+			return gd.FieldError([]string{"ID"}, userInstructTags[2], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.Int64(value)
+			data.ID = c
+			if cerr != nil {
+				return gd.FieldError([]string{"ID"}, userInstructTags[2], value, types.NewCoerceError(cerr))
+			}
+		}
+		if !dataWasSet && structDefaults0.FieldSet("ID", fieldValue) {
+			dataWasSet = true
+		}
+		if !dataWasSet {
+			return gd.RequiredError([]string{"ID"}, userInstructTags[2])
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Name"}, userInstructTags[3], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Name).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[3])
+		if err != nil {
+			return gd.FieldError([]string{"Name"}, userInstructTags[3], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Name = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Name"}, userInstructTags[3], value, types.NewCoerceError(cerr))
+			}
+		}
+		if !dataWasSet && structDefaults0.FieldSet("Name", fieldValue) {
+			dataWasSet = true
+		}
+		if !dataWasSet {
+			return gd.RequiredError([]string{"Name"}, userInstructTags[3])
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Nick"}, userInstructTags[4], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Nick).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[4])
+		if err != nil {
+			return gd.FieldError([]string{"Nick"}, userInstructTags[4], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Nick = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Nick"}, userInstructTags[4], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Active"}, userInstructTags[5], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Active).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[5])
+		if err != nil {
+			return gd.FieldError([]string{"Active"}, userInstructTags[5], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.Bool(value)
+			data.Active = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Active"}, userInstructTags[5], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Score"}, userInstructTags[6], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Score).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[6])
+		if err != nil {
+			return gd.FieldError([]string{"Score"}, userInstructTags[6], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.Float64(value)
+			data.Score = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Score"}, userInstructTags[6], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Level"}, userInstructTags[7], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Level).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[7])
+		if err != nil {
+			return gd.FieldError([]string{"Level"}, userInstructTags[7], nil, err)
+		}
+		if !dataWasSet {
+			dataWasSet, value = true, "3"
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.Uint8(value)
+			data.Level = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Level"}, userInstructTags[7], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Tags"}, userInstructTags[8], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Tags).Elem()
+		dataWasSet, value, err := gd.Decode(input, true, fieldValue, userInstructTags[8])
+		if err != nil {
+			return gd.FieldError([]string{"Tags"}, userInstructTags[8], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			if items, ok := value.([]string); ok {
+				list := make([]string, 0, len(items))
+				for _, item := range items {
+					c, cerr := coerce.String(item)
+					if cerr != nil {
+						return gd.FieldError([]string{"Tags"}, userInstructTags[8], value, types.NewCoerceError(cerr))
+					}
+					list = append(list, c)
+				}
+				data.Tags = list
+			} else if err = gd.Resolve(fieldValue, value, userInstructTags[8]); err != nil {
+				return gd.FieldError([]string{"Tags"}, userInstructTags[8], value, err)
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"IDs"}, userInstructTags[9], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.IDs).Elem()
+		dataWasSet, value, err := gd.Decode(input, true, fieldValue, userInstructTags[9])
+		if err != nil {
+			return gd.FieldError([]string{"IDs"}, userInstructTags[9], nil, err)
+		}
+		if !dataWasSet {
+			dataWasSet, value = true, []string{"5"}
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			if items, ok := value.([]string); ok {
+				list := make([]int, 0, len(items))
+				for _, item := range items {
+					c, cerr := coerce.Int(item)
+					if cerr != nil {
+						return gd.FieldError([]string{"IDs"}, userInstructTags[9], value, types.NewCoerceError(cerr))
+					}
+					list = append(list, c)
+				}
+				data.IDs = list
+			} else if err = gd.Resolve(fieldValue, value, userInstructTags[9]); err != nil {
+				return gd.FieldError([]string{"IDs"}, userInstructTags[9], value, err)
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Since"}, userInstructTags[10], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Since).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[10])
+		if err != nil {
+			return gd.FieldError([]string{"Since"}, userInstructTags[10], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			if err = gd.Resolve(fieldValue, value, userInstructTags[10]); err != nil {
+				return gd.FieldError([]string{"Since"}, userInstructTags[10], value, err)
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Labels"}, userInstructTags[11], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Labels).Elem()
+		dataWasSet, value, err := gd.Decode(input, true, fieldValue, userInstructTags[11])
+		if err != nil {
+			return gd.FieldError([]string{"Labels"}, userInstructTags[11], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			if err = gd.Resolve(fieldValue, value, userInstructTags[11]); err != nil {
+				return gd.FieldError([]string{"Labels"}, userInstructTags[11], value, err)
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Address"}, userInstructTags[12], nil, err)
+	}
+	structDefaults2 := gd.StructDefaults(&data.Address)
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Address", "City"}, userInstructTags[13], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Address.City).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[13])
+		if err != nil {
+			return gd.FieldError([]string{"Address", "City"}, userInstructTags[13], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Address.City = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Address", "City"}, userInstructTags[13], value, types.NewCoerceError(cerr))
+			}
+		}
+		if !dataWasSet && structDefaults2.FieldSet("City", fieldValue) {
+			dataWasSet = true
+		}
+		if !dataWasSet {
+			return gd.RequiredError([]string{"Address", "City"}, userInstructTags[13])
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Address", "Country"}, userInstructTags[14], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Address.Country).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[14])
+		if err != nil {
+			return gd.FieldError([]string{"Address", "Country"}, userInstructTags[14], nil, err)
+		}
+		if !dataWasSet {
+			dataWasSet, value = true, "BR"
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Address.Country = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Address", "Country"}, userInstructTags[14], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Billing"}, userInstructTags[15], nil, err)
+	}
+	if data.Billing == nil {
+		data.Billing = new(Address)
+	}
+	structDefaults3 := gd.StructDefaults(data.Billing)
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Billing", "City"}, userInstructTags[16], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Billing.City).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[16])
+		if err != nil {
+			return gd.FieldError([]string{"Billing", "City"}, userInstructTags[16], nil, err)
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Billing.City = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Billing", "City"}, userInstructTags[16], value, types.NewCoerceError(cerr))
+			}
+		}
+		if !dataWasSet && structDefaults3.FieldSet("City", fieldValue) {
+			dataWasSet = true
+		}
+		if !dataWasSet {
+			return gd.RequiredError([]string{"Billing", "City"}, userInstructTags[16])
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Billing", "Country"}, userInstructTags[17], nil, err)
+	}
+	{
+		fieldValue := reflect.ValueOf(&data.Billing.Country).Elem()
+		dataWasSet, value, err := gd.Decode(input, false, fieldValue, userInstructTags[17])
+		if err != nil {
+			return gd.FieldError([]string{"Billing", "Country"}, userInstructTags[17], nil, err)
+		}
+		if !dataWasSet {
+			dataWasSet, value = true, "BR"
+		}
+		if dataWasSet && value != instruct.IgnoreDecodeValue {
+			c, cerr := coerce.String(value)
+			data.Billing.Country = c
+			if cerr != nil {
+				return gd.FieldError([]string{"Billing", "Country"}, userInstructTags[17], value, types.NewCoerceError(cerr))
+			}
+		}
+	}
+	if err := gd.ContextErr(); err != nil {
+		return gd.FieldError([]string{"Internal"}, userInstructTags[18], nil, err)
+	}
+	return gd.Validate(input)
+}
+
+// DecodeItem decodes the input to the Item struct using [instruct.Decoder.Decode].
+// Generated code is not used because struct options are not supported.
+func DecodeItem[IT any, DC instruct.DecodeContext](dec *instruct.Decoder[IT, DC], input IT, data *Item,
+	decodeOptions instruct.DecodeOptions[IT, DC]) error {
+	return dec.Decode(input, data, decodeOptions)
+}
@@ -0,0 +1,63 @@
+// Command instruct-gen generates typed decode functions for structs with "instruct" tags.
+//
+// For each type it generates a "Decode<Type>" function that calls the decode operations and the coerce functions
+// directly, falling back to the reflective [instruct.Decoder] for anything it can't handle.
+//
+// Usage:
+//
+//	//go:generate go run github.com/rrgmc/instruct/cmd/instruct-gen -type=User,Item
+//
+// Flags:
+//
+//	-type      comma-separated list of type names (required)
+//	-tag       struct tag name, must be the same as the Decoder one (default "instruct")
+//	-required  default value of the "required" option, must be the same as the Decoder one (default true)
+//	-output    output file name (default "<first type>_instruct.go" in lowercase)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of type names")
+	tagName := flag.String("tag", "instruct", "struct tag name")
+	defaultRequired := flag.Bool("required", true, "default value of the required option")
+	output := flag.String("output", "", "output file name")
+	flag.Parse()
+
+	if *typeNames == "" {
+		fmt.Fprintln(os.Stderr, "instruct-gen: the -type flag is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_instruct.go"
+	}
+
+	src, err := generate(dir, types, options{
+		tagName:         *tagName,
+		defaultRequired: *defaultRequired,
+		args:            os.Args[1:],
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "instruct-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "instruct-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -2,12 +2,55 @@ package coerce
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// TimeEpoch is the unit of Unix epoch values coerced to time.Time.
+type TimeEpoch int
+
+const (
+	TimeEpochNone         TimeEpoch = iota // epoch values are not accepted.
+	TimeEpochSeconds                       // seconds since the Unix epoch.
+	TimeEpochMilliseconds                  // milliseconds since the Unix epoch.
+	TimeEpochMicroseconds                  // microseconds since the Unix epoch.
+	TimeEpochNanoseconds                   // nanoseconds since the Unix epoch.
+)
+
+func (e TimeEpoch) String() string {
+	switch e {
+	case TimeEpochNone:
+		return "none"
+	case TimeEpochSeconds:
+		return "seconds"
+	case TimeEpochMilliseconds:
+		return "milliseconds"
+	case TimeEpochMicroseconds:
+		return "microseconds"
+	case TimeEpochNanoseconds:
+		return "nanoseconds"
+	}
+	return fmt.Sprintf("TimeEpoch(%d)", int(e))
+}
+
+// TimeOptions are the options of TimeWithOptions.
+type TimeOptions struct {
+	Layouts  []string       // time.Parse layouts, tried in order.
+	Epoch    TimeEpoch      // unit of integers and numeric strings. TimeEpochNone doesn't accept them.
+	Location *time.Location // location of layouts without a zone, and of epoch values. Default UTC, like time.Parse.
+}
+
 // Time coerces v to time.Time.
 func Time(v interface{}, layout string) (time.Time, error) {
+	return TimeWithOptions(v, TimeOptions{Layouts: []string{layout}})
+}
+
+// TimeWithOptions coerces v to time.Time. Strings are parsed using each of the layouts in order, and if the
+// epoch is set, numeric strings which didn't match any layout and integers are parsed as epoch values.
+func TimeWithOptions(v interface{}, options TimeOptions) (time.Time, error) {
 	for {
 		switch sw := v.(type) {
 		case time.Time:
@@ -15,11 +58,27 @@ func Time(v interface{}, layout string) (time.Time, error) {
 		case nil:
 			return time.Time{}, nil
 		case string:
-			t, err := time.Parse(layout, sw)
-			if err != nil {
-				return time.Time{}, err
-			}
-			return t, nil
+			return parseTime(sw, options)
+		case int:
+			return epochTime(int64(sw), options)
+		case int8:
+			return epochTime(int64(sw), options)
+		case int16:
+			return epochTime(int64(sw), options)
+		case int32:
+			return epochTime(int64(sw), options)
+		case int64:
+			return epochTime(sw, options)
+		case uint:
+			return epochTimeUint(uint64(sw), options)
+		case uint8:
+			return epochTime(int64(sw), options)
+		case uint16:
+			return epochTime(int64(sw), options)
+		case uint32:
+			return epochTime(int64(sw), options)
+		case uint64:
+			return epochTimeUint(sw, options)
 		}
 		//
 		// Beyond this point we need reflection.
@@ -33,6 +92,12 @@ func Time(v interface{}, layout string) (time.Time, error) {
 		case reflect.String:
 			v = reflect.ValueOf(v).Convert(TypeString).Interface().(string)
 			continue
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v = reflect.ValueOf(v).Convert(TypeInt64).Interface().(int64)
+			continue
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v = reflect.ValueOf(v).Convert(TypeUint64).Interface().(uint64)
+			continue
 		case reflect.Ptr:
 			rv := reflect.ValueOf(v)
 			for ; rv.Kind() == reflect.Ptr; rv = rv.Elem() {
@@ -48,3 +113,78 @@ func Time(v interface{}, layout string) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("%w; coerce %v to time.Time", ErrUnsupported, v)
 	}
 }
+
+// parseTime parses the string using the layouts, and as an epoch value if it is numeric.
+// Without a location, time.Parse is used, which returns time.Local for zones matching it.
+func parseTime(s string, options TimeOptions) (time.Time, error) {
+	var lastErr error
+	for _, layout := range options.Layouts {
+		var t time.Time
+		var err error
+		if options.Location == nil {
+			t, err = time.Parse(layout, s)
+		} else {
+			t, err = time.ParseInLocation(layout, s, options.Location)
+		}
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+
+	tried := make([]string, 0, len(options.Layouts)+1)
+	for _, layout := range options.Layouts {
+		tried = append(tried, strconv.Quote(layout))
+	}
+
+	if options.Epoch != TimeEpochNone {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return epochTime(n, options)
+		} else if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+			return time.Time{}, fmt.Errorf("%w; %v overflows epoch %s", ErrOverflow, s, options.Epoch)
+		}
+		tried = append(tried, "epoch "+options.Epoch.String())
+	}
+
+	if len(tried) == 0 {
+		return time.Time{}, fmt.Errorf("%w; could not parse %q as time.Time: no layouts", ErrInvalid, s)
+	}
+	if lastErr != nil {
+		return time.Time{}, fmt.Errorf("%w; could not parse %q as time.Time using %s: %w", ErrInvalid, s,
+			strings.Join(tried, ", "), lastErr)
+	}
+	return time.Time{}, fmt.Errorf("%w; could not parse %q as time.Time using %s", ErrInvalid, s,
+		strings.Join(tried, ", "))
+}
+
+// epochTime converts an epoch value to time.Time.
+func epochTime(n int64, options TimeOptions) (time.Time, error) {
+	var t time.Time
+	switch options.Epoch {
+	case TimeEpochSeconds:
+		t = time.Unix(n, 0)
+	case TimeEpochMilliseconds:
+		t = time.UnixMilli(n)
+	case TimeEpochMicroseconds:
+		t = time.UnixMicro(n)
+	case TimeEpochNanoseconds:
+		t = time.Unix(0, n)
+	default:
+		return time.Time{}, fmt.Errorf("%w; coerce %v to time.Time without an epoch unit", ErrUnsupported, n)
+	}
+	return t.In(timeLocation(options)), nil
+}
+
+func epochTimeUint(n uint64, options TimeOptions) (time.Time, error) {
+	if n > math.MaxInt64 {
+		return time.Time{}, fmt.Errorf("%w; %v overflows epoch %s", ErrOverflow, n, options.Epoch)
+	}
+	return epochTime(int64(n), options)
+}
+
+func timeLocation(options TimeOptions) *time.Location {
+	if options.Location == nil {
+		return time.UTC
+	}
+	return options.Location
+}
@@ -89,6 +89,24 @@ func TestTimeFromString(t *testing.T) {
 	tests.Run(t)
 }
 
+func TestTimeFromStringLocal(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+	defer func(local *time.Location) {
+		time.Local = local
+	}(time.Local)
+	time.Local = loc
+
+	// an offset matching time.Local returns time.Local, like time.Parse.
+	ss := "2021-10-22T11:01:00-03:00"
+	s, _ := time.Parse(time.RFC3339, ss)
+
+	got, err := coerce.Time(ss, time.RFC3339)
+	assert.NoError(t, err)
+	assert.Equal(t, s, got)
+	assert.Equal(t, time.Local, got.Location())
+}
+
 func TestTimeFromStringKind(t *testing.T) {
 	ss := "2021-10-22T11:01:00Z"
 	s, _ := time.Parse(time.RFC3339, ss)
@@ -103,3 +121,111 @@ func TestTimeFromStringKind(t *testing.T) {
 	}
 	tests.Run(t)
 }
+
+func TestTimeWithOptions(t *testing.T) {
+	loc := time.FixedZone("-03", -3*60*60)
+	ts := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+
+	options := coerce.TimeOptions{
+		Layouts:  []string{time.RFC3339, time.DateOnly, "20060102"},
+		Epoch:    coerce.TimeEpochSeconds,
+		Location: loc,
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		options coerce.TimeOptions
+		expect  time.Time
+	}{
+		{name: "first layout", value: "2023-11-14T22:13:20Z", options: options, expect: ts},
+		{name: "second layout uses location", value: "2023-11-14", options: options,
+			expect: time.Date(2023, 11, 14, 0, 0, 0, 0, loc)},
+		{name: "numeric layout before epoch", value: "20231114", options: options,
+			expect: time.Date(2023, 11, 14, 0, 0, 0, 0, loc)},
+		{name: "epoch seconds string", value: "1700000000", options: options, expect: ts.In(loc)},
+		{name: "epoch seconds int", value: int64(1700000000), options: options, expect: ts.In(loc)},
+		{name: "epoch seconds uint", value: uint32(1700000000), options: options, expect: ts.In(loc)},
+		{name: "epoch milliseconds", value: 1700000000123,
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochMilliseconds},
+			expect:  ts.Add(123 * time.Millisecond)},
+		{name: "epoch microseconds", value: "1700000000000123",
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochMicroseconds},
+			expect:  ts.Add(123 * time.Microsecond)},
+		{name: "epoch nanoseconds", value: S("1700000000000000123"),
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochNanoseconds},
+			expect:  ts.Add(123)},
+		{name: "negative epoch", value: "-1", options: options,
+			expect: time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC).In(loc)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chk := assert.New(t)
+			s, err := coerce.TimeWithOptions(test.value, test.options)
+			chk.NoError(err)
+			chk.True(test.expect.Equal(s), "expected %v, got %v", test.expect, s)
+			chk.Equal(test.expect.Location(), s.Location())
+		})
+	}
+}
+
+func TestTimeWithOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		options coerce.TimeOptions
+		err     error
+		message string
+	}{
+		{
+			name:    "layouts tried",
+			value:   "14/11/2023",
+			options: coerce.TimeOptions{Layouts: []string{time.RFC3339, time.DateOnly}, Epoch: coerce.TimeEpochMilliseconds},
+			err:     coerce.ErrInvalid,
+			message: `coerce: invalid; could not parse "14/11/2023" as time.Time using "2006-01-02T15:04:05Z07:00", "2006-01-02", epoch milliseconds: parsing time "14/11/2023" as "2006-01-02": cannot parse "14/11/2023" as "2006"`,
+		},
+		{
+			name:    "epoch only",
+			value:   "now",
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochSeconds},
+			err:     coerce.ErrInvalid,
+			message: `coerce: invalid; could not parse "now" as time.Time using epoch seconds`,
+		},
+		{
+			name:    "no layouts",
+			value:   "now",
+			err:     coerce.ErrInvalid,
+			message: `coerce: invalid; could not parse "now" as time.Time: no layouts`,
+		},
+		{
+			name:    "int without epoch",
+			value:   1700000000,
+			options: coerce.TimeOptions{Layouts: []string{time.RFC3339}},
+			err:     coerce.ErrUnsupported,
+			message: "coerce: unsupported; coerce 1700000000 to time.Time without an epoch unit",
+		},
+		{
+			name:    "string overflow",
+			value:   "99999999999999999999",
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochSeconds},
+			err:     coerce.ErrOverflow,
+			message: "coerce: overflow; 99999999999999999999 overflows epoch seconds",
+		},
+		{
+			name:    "uint overflow",
+			value:   uint64(1 << 63),
+			options: coerce.TimeOptions{Epoch: coerce.TimeEpochNanoseconds},
+			err:     coerce.ErrOverflow,
+			message: "coerce: overflow; 9223372036854775808 overflows epoch nanoseconds",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chk := assert.New(t)
+			s, err := coerce.TimeWithOptions(test.value, test.options)
+			chk.ErrorIs(err, test.err)
+			chk.EqualError(err, test.message)
+			chk.Equal(time.Time{}, s)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package instruct
 
+import "context"
+
 // DecodeContext is the context sent to DecodeOperation.
 type DecodeContext interface {
 	// ValueUsed signals that the value was used.
@@ -10,8 +12,16 @@ type DecodeContext interface {
 	FieldNameMapper() FieldNameMapper
 }
 
+// DecodeContextWithContext is an optional DecodeContext extension that gives access to a [context.Context].
+// If implemented, decoding is stopped if the context is done, and operations can use it to check deadlines.
+type DecodeContextWithContext interface {
+	// Context returns the context of the decode call. It must never return nil.
+	Context() context.Context
+}
+
 // DefaultDecodeContext implements the standard decode context.
 type DefaultDecodeContext struct {
+	ctx             context.Context
 	fieldNameMapper FieldNameMapper
 	usedValues      map[string]map[string]bool
 }
@@ -48,3 +58,24 @@ func (d *DefaultDecodeContext) GetUsedValues(operation string) map[string]bool {
 func (d *DefaultDecodeContext) FieldNameMapper() FieldNameMapper {
 	return d.fieldNameMapper
 }
+
+// Context returns the context set with SetContext, or [context.Background] if none was set.
+func (d *DefaultDecodeContext) Context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
+// SetContext sets the context of the decode call.
+func (d *DefaultDecodeContext) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// decodeContextErr returns the context error if the decode context implements DecodeContextWithContext.
+func decodeContextErr[DC DecodeContext](ctx DC) error {
+	if c, ok := any(ctx).(DecodeContextWithContext); ok {
+		return c.Context().Err()
+	}
+	return nil
+}
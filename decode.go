@@ -3,11 +3,13 @@ package instruct
 import (
 	"errors"
 	"reflect"
+	"sync"
 )
 
 // Decoder decodes inputs to structs.
 type Decoder[IT any, DC DecodeContext] struct {
-	options DefaultOptions[IT, DC]
+	options          DefaultOptions[IT, DC]
+	generatedChecked sync.Map // map[reflect.Type]bool, whether generated code can decode the type.
 }
 
 // NewDecoder creates a Decoder instance without any decode operations. At least one must be added for
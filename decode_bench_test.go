@@ -0,0 +1,99 @@
+package instruct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type benchmarkDecodeData struct {
+	H   string   `instruct:"values"`
+	Q   int      `instruct:"values"`
+	F   float64  `instruct:"values"`
+	B   bool     `instruct:"values"`
+	L   []int32  `instruct:"values"`
+	P   *int     `instruct:"values"`
+	U   uint16   `instruct:"values"`
+	LS  []string `instruct:"values"`
+	Opt string   `instruct:"values,required=false"`
+	In  struct {
+		X int64  `instruct:"values"`
+		Y string `instruct:"values"`
+	} `instruct:"recurse"`
+}
+
+// benchmarkDecodeOperation returns values from a pre-parsed map, so the benchmarks measure the decoder and not
+// the input parsing. Returning the values as "any" still allocates once per field.
+type benchmarkDecodeOperation struct {
+	values url.Values
+}
+
+func (d *benchmarkDecodeOperation) Decode(ctx TestDecodeContext, r *http.Request, isList bool, field reflect.Value, tag *Tag) (bool, any, error) {
+	v, ok := d.values[tag.Name]
+	if !ok {
+		return false, nil, nil
+	}
+	if isList {
+		return true, v, nil
+	}
+	return true, v[0], nil
+}
+
+// benchmarkResolver hides the optional interfaces of the default resolver, to decode without the precompiled
+// resolve functions.
+type benchmarkResolver struct {
+	r Resolver
+}
+
+func (r benchmarkResolver) Resolve(target reflect.Value, value any) error {
+	return r.r.Resolve(target, value)
+}
+
+func benchmarkDecode(b *testing.B, defOpt DefaultOptions[*http.Request, TestDecodeContext]) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	defOpt.DecodeOperations["values"] = &benchmarkDecodeOperation{
+		values: url.Values{
+			"h":  {"hv"},
+			"q":  {"12"},
+			"f":  {"1.5"},
+			"b":  {"true"},
+			"l":  {"1", "2", "3"},
+			"p":  {"5"},
+			"u":  {"7"},
+			"ls": {"a", "b"},
+			"x":  {"99"},
+			"y":  {"yv"},
+		},
+	}
+	defOpt.StructInfoCache(true)
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	decOpt := GetTestDecoderDecodeOptions(nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var data benchmarkDecodeData
+		if err := dec.Decode(r, &data, decOpt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	benchmarkDecode(b, GetTestDecoderOptions())
+}
+
+func BenchmarkDecodeWithoutResolvePlan(b *testing.B) {
+	defOpt := GetTestDecoderOptions()
+	defOpt.Resolver = benchmarkResolver{defOpt.Resolver}
+	benchmarkDecode(b, defOpt)
+}
+
+func BenchmarkDecodeTagFormatResolver(b *testing.B) {
+	defOpt := GetTestDecoderOptions()
+	defOpt.Resolver = NewTagFormatResolver(defOpt.Resolver)
+	benchmarkDecode(b, defOpt)
+}
@@ -0,0 +1,125 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/rrgmc/instruct/resolver"
+	"github.com/rrgmc/instruct/types"
+)
+
+// GeneratedDecoder is used by the code generated by "cmd/instruct-gen" to call the decode operations and the
+// resolver of a Decoder directly, without building a structInfo.
+type GeneratedDecoder[IT any, DC DecodeContext] struct {
+	d             *Decoder[IT, DC]
+	decodeOptions DecodeOptions[IT, DC]
+}
+
+// Generated returns a GeneratedDecoder if the code generated for typ with the passed tag name and default
+// required setting gives the same results as the reflective decoder for the current options.
+// Generated code supports only the default FieldNameMapper and the default Resolver without custom types, and
+// no NameFromTags, MapTags or error aggregation. If any operation declares its tag options, they are checked once
+// per type. If false is returned, the generated code must call [Decoder.Decode] instead.
+func (d *Decoder[IT, DC]) Generated(typ reflect.Type, tagName string, defaultRequired bool,
+	decodeOptions DecodeOptions[IT, DC]) (*GeneratedDecoder[IT, DC], bool) {
+	if isZero(decodeOptions.Ctx) || decodeOptions.MapTags != nil || d.aggregateErrors(decodeOptions) ||
+		d.options.TagName != tagName || d.options.DefaultRequired != defaultRequired {
+		return nil, false
+	}
+	if len(d.options.NameFromTags) > 0 || d.options.FieldNameMapper == nil ||
+		reflect.ValueOf(d.options.FieldNameMapper).Pointer() != reflect.ValueOf(DefaultFieldNameMapper).Pointer() {
+		return nil, false
+	}
+	// generated code calls the coerce functions directly for primitive types, so custom types must not be set.
+	if r, ok := d.options.Resolver.(*resolver.Resolver); !ok || !r.CoercesPrimitives() {
+		return nil, false
+	}
+	if d.options.defaultMapTags != nil && d.options.defaultMapTags.Get(reflectElem(typ)) != nil {
+		return nil, false
+	}
+	if !d.generatedTagOptionsValid(typ) {
+		return nil, false
+	}
+	return &GeneratedDecoder[IT, DC]{
+		d:             d,
+		decodeOptions: decodeOptions,
+	}, true
+}
+
+// generatedTagOptionsValid returns whether the tag options of typ are valid for the operations implementing
+// DecodeOperationOptionsSpec, which generated code doesn't check. The struct info is built once per type to
+// check them, and if they are not valid the reflective decoder returns the same error as without generated code.
+func (d *Decoder[IT, DC]) generatedTagOptionsValid(typ reflect.Type) bool {
+	hasOptionsSpec := false
+	for _, operation := range d.options.DecodeOperations {
+		if _, ok := operation.(DecodeOperationOptionsSpec); ok {
+			hasOptionsSpec = true
+			break
+		}
+	}
+	if !hasOptionsSpec {
+		return true
+	}
+	typ = reflectElem(typ)
+	if valid, ok := d.generatedChecked.Load(typ); ok {
+		return valid.(bool)
+	}
+	_, err := d.structInfoFromType(typ)
+	d.generatedChecked.Store(typ, err == nil)
+	return err == nil
+}
+
+// ContextErr returns the error of the [context.Context] of the decode context, if it supports it.
+func (g *GeneratedDecoder[IT, DC]) ContextErr() error {
+	return decodeContextErr(g.decodeOptions.Ctx)
+}
+
+// StructDefaults calls [StructDefaults.Defaults] if data, a pointer to a struct, implements it, and returns the
+// snapshot used to check which fields were set by it. It returns nil if data doesn't implement it.
+func (g *GeneratedDecoder[IT, DC]) StructDefaults(data any) *StructDefaultsSnapshot {
+	return callStructDefaults(reflect.ValueOf(data).Elem())
+}
+
+// Decode calls the decode operation of the tag.
+func (g *GeneratedDecoder[IT, DC]) Decode(input IT, isList bool, field reflect.Value, tag *Tag) (bool, any, error) {
+	operation, ok := g.d.options.DecodeOperations[tag.Operation]
+	if !ok {
+		return false, nil, fmt.Errorf("%w '%s'", types.ErrUnknownOperation, tag.Operation)
+	}
+	return operation.Decode(g.decodeOptions.Ctx, input, isList, field, tag)
+}
+
+// Resolve resolves the value to the target using the Resolver, for types that generated code doesn't support.
+func (g *GeneratedDecoder[IT, DC]) Resolve(target reflect.Value, value any, tag *Tag) error {
+	if ro, ok := g.d.options.Resolver.(ResolverWithOptions); ok {
+		return ro.ResolveOptions(target, value, &tag.Options)
+	}
+	return g.d.options.Resolver.Resolve(target, value)
+}
+
+// Validate calls the validation of all decode operations, which is done after all fields are decoded.
+func (g *GeneratedDecoder[IT, DC]) Validate(input IT) error {
+	for _, operation := range g.d.options.DecodeOperations {
+		if v, ok := operation.(DecodeOperationValidate[IT, DC]); ok {
+			if err := v.Validate(g.decodeOptions.Ctx, input); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FieldError creates a [types.FieldError] for the field.
+func (g *GeneratedDecoder[IT, DC]) FieldError(fieldPath []string, tag *Tag, value any, err error) *types.FieldError {
+	return newFieldError(&structInfo{path: fieldPath, tag: tag}, value, err)
+}
+
+// RequiredError creates a [types.FieldError] with a [types.RequiredError] for the field.
+func (g *GeneratedDecoder[IT, DC]) RequiredError(fieldPath []string, tag *Tag) *types.FieldError {
+	si := &structInfo{path: fieldPath, tag: tag}
+	return newFieldError(si, nil, types.RequiredError{
+		Operation: tag.Operation,
+		FieldName: si.fullFieldName(),
+		TagName:   tag.Name,
+	})
+}
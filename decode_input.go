@@ -27,14 +27,14 @@ func (d *Decoder[IT, DC]) decodeInputFromStructInfo(input IT, si *structInfo, da
 
 	rv := reflect.ValueOf(data)
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
-		return &types.InvalidDecodeError{reflect.TypeOf(data)}
+		return &types.InvalidDecodeError{Type: reflect.TypeOf(data)}
 	}
 
 	if decodeOptions.MapTags != nil {
 		var err error
 
 		// if there is a decode-specific map tag, create a new struct info based on the default one.
-		si, err = structInfoWithMapTags(si, decodeOptions.MapTags, d.options)
+		si, err = structInfoWithMapTags(si, decodeOptions.MapTags, d.options.structInfoOptions())
 		if err != nil {
 			return err
 		}
@@ -68,5 +68,5 @@ func (d *Decoder[IT, DC]) structInfoFromType(typ reflect.Type) (*structInfo, err
 		return nil, fmt.Errorf("can only decode to struct, received: %v", typ.Kind())
 	}
 
-	return d.options.structInfoProvider.provide(typ, d.options.defaultMapTags.Get(typ), d.options)
+	return d.options.structInfoProvider.provide(typ, d.options.defaultMapTags.Get(typ), d.options.structInfoOptions())
 }
@@ -1,13 +1,24 @@
 package instruct
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/rrgmc/instruct/types"
 )
 
+// aggregateErrors returns whether to aggregate the field errors, using the DecodeOptions setting if set, or the
+// DefaultOptions one.
+func (d *Decoder[IT, DC]) aggregateErrors(decodeOptions DecodeOptions[IT, DC]) bool {
+	if decodeOptions.AggregateErrors != nil {
+		return *decodeOptions.AggregateErrors
+	}
+	return d.options.AggregateErrors
+}
+
 // decodeStruct uses the structInfo to decode the input to the struct.
+// If aggregating errors, all fields are decoded and a [types.DecodeErrors] is returned with all the failures.
 func (d *Decoder[IT, DC]) decodeStruct(si *structInfo, input IT, dataValue reflect.Value, decodeOptions DecodeOptions[IT, DC]) error {
 	reflectEnsurePointerValue(&dataValue)
 	dataValue = reflectValueElem(dataValue)
@@ -15,15 +26,33 @@ func (d *Decoder[IT, DC]) decodeStruct(si *structInfo, input IT, dataValue refle
 		return err
 	}
 
+	aggregate := d.aggregateErrors(decodeOptions)
+	var errs types.DecodeErrors
+
+	// set computed default values if the struct supports it.
+	structDefaults := callStructDefaults(dataValue)
+
 	// execute the struct operation (using StructOption or inner struct tags). Only executed if "when" is
 	// configured as "before".
+	if si.isStructOperation(SOOptionWhenBefore) {
+		if err := decodeContextErr(decodeOptions.Ctx); err != nil {
+			return stopFieldError(aggregate, errs, si, err)
+		}
+	}
 	err := d.executeStructOperation(SOOptionWhenBefore, dataValue, si, input, decodeOptions)
 	if err != nil {
-		return err
+		if !aggregate {
+			return err
+		}
+		errs = appendFieldError(errs, si, err)
 	}
 
 	for _, sifield := range si.fields {
-		fieldValue := dataValue.FieldByIndex(sifield.field.Index)
+		if err := decodeContextErr(decodeOptions.Ctx); err != nil {
+			return stopFieldError(aggregate, errs, sifield, err)
+		}
+
+		fieldValue := sifield.plan.fieldValue(dataValue)
 
 		dataWasSet := false
 
@@ -33,41 +62,126 @@ func (d *Decoder[IT, DC]) decodeStruct(si *structInfo, input IT, dataValue refle
 		case OperationRecurse:
 			// recurse into inner struct
 			if err := d.decodeStruct(sifield, input, fieldValue, decodeOptions); err != nil {
-				return err
+				if !aggregate {
+					return err
+				}
+				errs = appendFieldError(errs, sifield, err)
+				if decodeContextErr(decodeOptions.Ctx) != nil {
+					// the inner struct already reported where decoding stopped.
+					return errs
+				}
+				continue
 			}
 			dataWasSet = true
+		case OperationRecurseList:
+			// decode a slice of inner structs
+			var err error
+			dataWasSet, err = d.decodeStructList(sifield, input, fieldValue, decodeOptions)
+			if err != nil {
+				if !aggregate {
+					return err
+				}
+				errs = appendFieldError(errs, sifield, err)
+				if decodeContextErr(decodeOptions.Ctx) != nil {
+					// the list elements already reported where decoding stopped.
+					return errs
+				}
+				continue
+			}
 		default:
 			var err error
 			// execute operation (query, header, etc.)
 			dataWasSet, err = d.executeOperation(fieldValue, sifield, input, decodeOptions)
 			if err != nil {
-				return err
+				if !aggregate {
+					return err
+				}
+				errs = appendFieldError(errs, sifield, err)
+				continue
 			}
 		}
 
+		if !dataWasSet && structDefaults.fieldSet(sifield.field.Index, fieldValue) {
+			// value was set by StructDefaults
+			dataWasSet = true
+		}
+
 		if !dataWasSet && sifield.tag.Required {
-			return types.RequiredError{
+			err := newFieldError(sifield, nil, types.RequiredError{
 				Operation: sifield.tag.Operation,
 				FieldName: sifield.fullFieldName(),
 				TagName:   sifield.tag.Name,
+			})
+			if !aggregate {
+				return err
 			}
+			errs = appendFieldError(errs, sifield, err)
 		}
 	}
 
 	// execute the struct operation (using StructOption or inner struct tags). Only executed if "when" is
 	// configured as "after".
+	if si.isStructOperation(SOOptionWhenAfter) {
+		if err := decodeContextErr(decodeOptions.Ctx); err != nil {
+			return stopFieldError(aggregate, errs, si, err)
+		}
+	}
 	err = d.executeStructOperation(SOOptionWhenAfter, dataValue, si, input, decodeOptions)
 	if err != nil {
-		return err
+		if !aggregate {
+			return err
+		}
+		errs = appendFieldError(errs, si, err)
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
+// appendFieldError appends the error to the list as a [types.FieldError]. If the error is a [types.DecodeErrors]
+// returned by an inner struct, its items are appended directly.
+func appendFieldError(errs types.DecodeErrors, si *structInfo, err error) types.DecodeErrors {
+	var derrs types.DecodeErrors
+	if errors.As(err, &derrs) {
+		return append(errs, derrs...)
+	}
+	var ferr *types.FieldError
+	if errors.As(err, &ferr) {
+		return append(errs, ferr)
+	}
+	return append(errs, newFieldError(si, nil, err))
+}
+
+// stopFieldError returns the error that stops decoding. If aggregating errors, the previous errors are also
+// returned.
+func stopFieldError(aggregate bool, errs types.DecodeErrors, si *structInfo, err error) error {
+	ferr := newFieldError(si, nil, err)
+	if !aggregate {
+		return ferr
+	}
+	return append(errs, ferr)
+}
+
+// newFieldError creates a [types.FieldError] from the structInfo field.
+func newFieldError(si *structInfo, value any, err error) *types.FieldError {
+	ret := &types.FieldError{
+		FieldPath: si.path,
+		Value:     value,
+		Err:       err,
+	}
+	if si.tag != nil {
+		ret.Operation = si.tag.Operation
+		ret.TagName = si.tag.Name
+	}
+	return ret
+}
+
 // executeStructOperation execute the struct operation (using StructOption or inner struct tags).
 func (d *Decoder[IT, DC]) executeStructOperation(when string, dataValue reflect.Value, si *structInfo,
 	input IT, decodeOptions DecodeOptions[IT, DC]) error {
-	if si.tag == nil || !si.tag.IsSO || soOptionValue(si.tag.SOWhen) != when {
+	if !si.isStructOperation(when) {
 		return nil
 	}
 
@@ -76,52 +190,69 @@ func (d *Decoder[IT, DC]) executeStructOperation(when string, dataValue reflect.
 		return err
 	}
 	if !dataWasSet && si.tag.Required {
-		fn := si.fullFieldName()
-		if fn == "" {
-			fn = si.typ.String()
-		}
-
-		return types.RequiredError{
+		return newFieldError(si, nil, types.RequiredError{
 			IsStructOption: true,
 			Operation:      si.tag.Operation,
 			FieldName:      structFieldName(si.typ, si.fullFieldName()),
 			TagName:        si.tag.Name,
-		}
+		})
 	}
 	return nil
 }
 
 // executeOperation executes an operation (query, header, etc) on a struct field.
 // If the decode interface return IgnoreDecodeValue, the value is not set to it.
+// All returned errors are of type [types.FieldError].
 func (d *Decoder[IT, DC]) executeOperation(field reflect.Value, sifield *structInfo, input IT,
 	decodeOptions DecodeOptions[IT, DC]) (bool, error) {
 	// check if the operation exists
 	operation, opok := d.options.DecodeOperations[sifield.tag.Operation]
 	if !opok {
-		return false, fmt.Errorf("unknown operation '%s' for field %s", sifield.tag.Operation, sifield.field.Name)
+		return false, newFieldError(sifield, nil, fmt.Errorf("%w '%s'", types.ErrUnknownOperation, sifield.tag.Operation))
 	}
 
-	// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be checked as an array
-	isPrimitive := field.Type().PkgPath() == ""
-	isList := isPrimitive && (field.Kind() == reflect.Slice || field.Kind() == reflect.Array)
+	var isList bool
+	if sifield.plan != nil {
+		isList = sifield.plan.isList
+	} else {
+		// only check slices/arrays/maps for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+		isPrimitive := field.Type().PkgPath() == ""
+		isList = isPrimitive && (field.Kind() == reflect.Slice || field.Kind() == reflect.Array ||
+			field.Kind() == reflect.Map)
+	}
 
 	// call the decoder interface.
 	dataWasSet, value, err := operation.Decode(decodeOptions.Ctx, input, isList, field, sifield.tag)
 	if err != nil {
-		return false, err
+		return false, newFieldError(sifield, nil, err)
+	}
+
+	if !dataWasSet && sifield.tag.HasDefault {
+		// use the default value, which counts as set.
+		dataWasSet, value = true, sifield.tag.defaultValue(isList)
 	}
 
 	if dataWasSet && value != IgnoreDecodeValue {
 		if sifield.field.Type == nil {
 			// struct option can't be set as a value
-			return false, types.OperationNotSupportedError{
+			return false, newFieldError(sifield, value, types.OperationNotSupportedError{
 				Operation: sifield.tag.Operation,
 				FieldName: structFieldName(sifield.typ, sifield.fullFieldName()),
-			}
+			})
 		}
 
-		if err = d.options.Resolver.Resolve(field, value); err != nil {
-			return false, fmt.Errorf("error resolving field '%s': %w", sifield.fullFieldName(), err)
+		if sifield.plan != nil && sifield.plan.resolve != nil {
+			// the resolve function was selected when building the struct info.
+			err = sifield.plan.resolve(field, value, &sifield.tag.Options)
+		} else if tr, ok := d.options.Resolver.(TagResolver); ok {
+			err = tr.ResolveTag(field, value, sifield.tag)
+		} else if ro, ok := d.options.Resolver.(ResolverWithOptions); ok {
+			err = ro.ResolveOptions(field, value, &sifield.tag.Options)
+		} else {
+			err = d.options.Resolver.Resolve(field, value)
+		}
+		if err != nil {
+			return false, newFieldError(sifield, value, err)
 		}
 	}
 
@@ -0,0 +1,97 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// decodeStructList decodes a slice of structs for the "recurse_list" operation. The element indexes are found
+// from the names returned by the operations implementing [DecodeOperationNames], formatted by the ListKeyFormat.
+// Indexes are sorted and compacted, so "items[1]" and "items[5]" results in a slice of 2 elements, but field
+// paths in errors use the original index.
+func (d *Decoder[IT, DC]) decodeStructList(sifield *structInfo, input IT, fieldValue reflect.Value,
+	decodeOptions DecodeOptions[IT, DC]) (bool, error) {
+	keyFormat := d.options.ListKeyFormat
+	if keyFormat == nil {
+		keyFormat = ListKeyFormatBrackets
+	}
+
+	indexes, err := d.decodeStructListIndexes(sifield, input, keyFormat, decodeOptions)
+	if err != nil {
+		return false, newFieldError(sifield, nil, err)
+	}
+	if len(indexes) == 0 {
+		return false, nil
+	}
+
+	aggregate := d.aggregateErrors(decodeOptions)
+	var errs types.DecodeErrors
+
+	list := reflect.MakeSlice(fieldValue.Type(), len(indexes), len(indexes))
+	for i, index := range indexes {
+		elemsi := sifield.elem.withListIndex(len(sifield.path), sifield.tag.Name, index, keyFormat, true)
+		if err := d.decodeStruct(elemsi, input, list.Index(i), decodeOptions); err != nil {
+			if !aggregate {
+				return false, err
+			}
+			errs = appendFieldError(errs, elemsi, err)
+			if decodeContextErr(decodeOptions.Ctx) != nil {
+				return false, errs
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return false, errs
+	}
+
+	fieldValue.Set(list)
+	return true, nil
+}
+
+// decodeStructListIndexes returns the sorted element indexes of the list found in the input.
+func (d *Decoder[IT, DC]) decodeStructListIndexes(sifield *structInfo, input IT, keyFormat ListKeyFormat,
+	decodeOptions DecodeOptions[IT, DC]) ([]int, error) {
+	operations := map[string]bool{}
+	sifield.elem.collectOperations(operations)
+
+	found := map[int]bool{}
+	hasNames := false
+	for opname := range operations {
+		operation, ok := d.options.DecodeOperations[opname]
+		if !ok {
+			return nil, fmt.Errorf("%w '%s'", types.ErrUnknownOperation, opname)
+		}
+		opnames, ok := operation.(DecodeOperationNames[IT, DC])
+		if !ok {
+			continue
+		}
+		hasNames = true
+		names, err := opnames.Names(decodeOptions.Ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if index, ok := keyFormat.Index(sifield.tag.Name, name); ok {
+				found[index] = true
+			}
+		}
+	}
+	if !hasNames {
+		return nil, fmt.Errorf("no operation of the list elements supports listing names")
+	}
+
+	maxLength := d.options.ListMaxLength
+	if maxLength > 0 && len(found) > maxLength {
+		return nil, fmt.Errorf("%w: %d elements found, maximum is %d", types.ErrListMaxLength, len(found), maxLength)
+	}
+
+	indexes := make([]int, 0, len(found))
+	for index := range found {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
@@ -1,12 +1,16 @@
 package instruct
 
 import (
+	"context"
 	"encoding/xml"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rrgmc/instruct/resolver"
 	"github.com/rrgmc/instruct/types"
@@ -752,7 +756,7 @@ func TestDecodeSliceToNonSliceError(t *testing.T) {
 	require.ErrorIs(t, err, types.ErrCoerceUnsupported)
 }
 
-func TestDecodeMapFieldNotSupported(t *testing.T) {
+func TestDecodeMapFieldInvalid(t *testing.T) {
 	type DataType struct {
 		Val map[string]bool `instruct:"manual"`
 	}
@@ -765,7 +769,36 @@ func TestDecodeMapFieldNotSupported(t *testing.T) {
 		"val": "x",
 	}))
 	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
-	require.ErrorIs(t, err, types.ErrCoerceUnknown)
+	require.ErrorIs(t, err, types.ErrCoerceInvalid)
+}
+
+func TestDecodeMapField(t *testing.T) {
+	type DataType struct {
+		Val      map[string]string   `instruct:"query"`
+		ValInt   map[string]int      `instruct:"query,explodesep=;,mapsep=;,mapkvsep=:"`
+		ValSlice map[string][]string `instruct:"header"`
+		ValMap   map[string]int      `instruct:"manual"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	q := r.URL.Query()
+	q.Add("val", "k1=v1,k2=v2")
+	q.Add("valint", "a:1;b:2")
+	r.URL.RawQuery = q.Encode()
+	r.Header.Add("valslice", "k1=a")
+	r.Header.Add("valslice", "k1=b,k2=c")
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptionsWithManual(map[string]any{
+		"valmap": map[string]any{"x": "10", "y": 11},
+	}))
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"k1": "v1", "k2": "v2"}, data.Val)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, data.ValInt)
+	require.Equal(t, map[string][]string{"k1": {"a", "b"}, "k2": {"c"}}, data.ValSlice)
+	require.Equal(t, map[string]int{"x": 10, "y": 11}, data.ValMap)
 }
 
 func TestDecodeEnsureUsed(t *testing.T) {
@@ -799,3 +832,599 @@ func TestDecodeUnknownOperation(t *testing.T) {
 	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
 	require.Error(t, err)
 }
+
+func TestDecodeAggregateErrors(t *testing.T) {
+	type Inner struct {
+		IVal int `instruct:"header"`
+	}
+
+	type DataType struct {
+		Val   int    `instruct:"query"`
+		Val2  string `instruct:"header"`
+		Val3  string `instruct:"header"`
+		Inner Inner  `instruct:"recurse"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?val=x1", nil)
+	r.Header.Set("val3", "x3")
+	r.Header.Set("ival", "x4")
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	decOpt := GetTestDecoderDecodeOptions(nil)
+	decOpt.AggregateErrors = ptrTo(true)
+	err := dec.Decode(r, &data, decOpt)
+
+	var derrs types.DecodeErrors
+	require.ErrorAs(t, err, &derrs)
+	require.Len(t, derrs, 3)
+
+	require.Equal(t, []string{"Val"}, derrs[0].FieldPath)
+	require.Equal(t, TestOperationQuery, derrs[0].Operation)
+	require.Equal(t, "val", derrs[0].TagName)
+	require.Equal(t, "x1", derrs[0].Value)
+	require.ErrorIs(t, derrs[0], types.ErrCoerceInvalid)
+
+	require.Equal(t, []string{"Val2"}, derrs[1].FieldPath)
+	require.Equal(t, TestOperationHeader, derrs[1].Operation)
+	require.Equal(t, "val2", derrs[1].TagName)
+	var reqErr types.RequiredError
+	require.ErrorAs(t, derrs[1], &reqErr)
+
+	require.Equal(t, []string{"Inner", "IVal"}, derrs[2].FieldPath)
+	require.Equal(t, "ival", derrs[2].TagName)
+
+	require.ErrorIs(t, err, types.ErrCoerceInvalid)
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, "x3", data.Val3)
+}
+
+func TestDecodeAggregateErrorsDefaultOption(t *testing.T) {
+	type DataType struct {
+		Val  string `instruct:"header"`
+		Val2 string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.AggregateErrors = true
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var derrs types.DecodeErrors
+	require.ErrorAs(t, err, &derrs)
+	require.Len(t, derrs, 2)
+
+	// a call can opt out of the decoder-wide aggregation.
+	decOpt := GetTestDecoderDecodeOptions(nil)
+	decOpt.AggregateErrors = ptrTo(false)
+	err = dec.Decode(r, &data, decOpt)
+	require.False(t, errors.As(err, &derrs))
+	var reqErr types.RequiredError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, "Val", reqErr.FieldName)
+}
+
+func TestDecodeFailFastByDefault(t *testing.T) {
+	type DataType struct {
+		Val  string `instruct:"header"`
+		Val2 string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var derrs types.DecodeErrors
+	require.False(t, errors.As(err, &derrs))
+	var reqErr types.RequiredError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, "Val", reqErr.FieldName)
+}
+
+func TestDecodeFieldError(t *testing.T) {
+	type Inner struct {
+		IVal int `instruct:"query"`
+	}
+
+	type DataType struct {
+		Inner Inner `instruct:"recurse"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?ival=x1", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Inner", "IVal"}, ferr.FieldPath)
+	require.Equal(t, TestOperationQuery, ferr.Operation)
+	require.Equal(t, "ival", ferr.TagName)
+	require.Equal(t, "x1", ferr.Value)
+	require.ErrorIs(t, err, types.ErrCoerceInvalid)
+	var cerr types.CoerceError
+	require.ErrorAs(t, err, &cerr)
+}
+
+func TestDecodeFieldErrorRequired(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Val"}, ferr.FieldPath)
+	require.Equal(t, TestOperationHeader, ferr.Operation)
+	require.Equal(t, "val", ferr.TagName)
+	require.Nil(t, ferr.Value)
+	var reqErr types.RequiredError
+	require.ErrorAs(t, err, &reqErr)
+}
+
+func TestDecodeFieldErrorOperation(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"body"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Val"}, ferr.FieldPath)
+	require.Equal(t, TestOperationBody, ferr.Operation)
+	require.EqualError(t, ferr.Err, "body operation not allowed")
+}
+
+func TestDecodeFieldErrorUnknownOperation(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"invalid_operation"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Val"}, ferr.FieldPath)
+	require.Equal(t, "invalid_operation", ferr.Operation)
+	require.ErrorIs(t, err, types.ErrUnknownOperation)
+}
+
+func TestDecodeDefault(t *testing.T) {
+	type Inner struct {
+		IVal string `instruct:"header,default=inner"`
+	}
+
+	type DataType struct {
+		Val      int       `instruct:"query,default=12"`
+		Val2     string    `instruct:"query,default=abc"`
+		ValFound string    `instruct:"query,default=abc"`
+		ValPtr   *int64    `instruct:"header,default=99"`
+		ValSlice []int32   `instruct:"header,default=15"`
+		ValEmpty []string  `instruct:"header,default="`
+		ValTime  time.Time `instruct:"query,default=2021-10-22T11:01:00Z"`
+		Inner    Inner     `instruct:"recurse"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?valfound=x1", nil)
+
+	var data DataType
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.Resolver = resolver.NewResolver(resolver.WithValueResolver(resolver.NewDefaultValueResolver(
+		resolver.WithCustomType(resolver.NewValueResolverTime(time.RFC3339)))))
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+
+	t1, _ := time.Parse(time.RFC3339, "2021-10-22T11:01:00Z")
+
+	require.Equal(t, 12, data.Val)
+	require.Equal(t, "abc", data.Val2)
+	require.Equal(t, "x1", data.ValFound)
+	require.NotNil(t, data.ValPtr)
+	require.Equal(t, int64(99), *data.ValPtr)
+	require.Equal(t, []int32{15}, data.ValSlice)
+	require.Equal(t, []string{}, data.ValEmpty)
+	require.Equal(t, t1, data.ValTime)
+	require.Equal(t, "inner", data.Inner.IVal)
+}
+
+func TestDecodeDefaultMapTags(t *testing.T) {
+	type DataType struct {
+		Val int
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), MapTags{
+		"Val": "query,default=45",
+	})
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, 45, data.Val)
+}
+
+func TestDecodeDefaultError(t *testing.T) {
+	type DataType struct {
+		Val int `instruct:"query,default=invalid"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, "invalid", ferr.Value)
+	require.ErrorIs(t, err, types.ErrCoerceInvalid)
+}
+
+type testStructDefaults struct {
+	Val  string `instruct:"header"`
+	Val2 int    `instruct:"header"`
+	Val3 string `instruct:"header,required=false"`
+}
+
+func (s *testStructDefaults) Defaults() {
+	s.Val = "default-val"
+	s.Val2 = 10
+}
+
+func TestDecodeStructDefaults(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("val2", "20")
+
+	var data testStructDefaults
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, "default-val", data.Val)
+	require.Equal(t, 20, data.Val2)
+	require.Equal(t, "", data.Val3)
+}
+
+type testStructDefaultsPartial struct {
+	Val  string `instruct:"header"`
+	Val2 int    `instruct:"header"`
+}
+
+func (s *testStructDefaultsPartial) Defaults() {
+	s.Val = "default-val"
+}
+
+func TestDecodeStructDefaultsPrefilledRequired(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Val2 was set by the caller, not by Defaults, so it doesn't satisfy required.
+	data := testStructDefaultsPartial{Val2: 5}
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	var reqErr types.RequiredError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, "Val2", reqErr.FieldName)
+}
+
+type testStructDefaultsInterface struct {
+	Extra any `instruct:"header"`
+}
+
+func (s *testStructDefaultsInterface) Defaults() {
+	s.Extra = []string{"a", "b"}
+}
+
+func TestDecodeStructDefaultsInterface(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// the interface holds a slice before and after Defaults, which can't be compared with ==.
+	data := testStructDefaultsInterface{Extra: []string{"x"}}
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, data.Extra)
+}
+
+type testDecodeOperationContext struct {
+	cancel context.CancelFunc
+}
+
+func (d *testDecodeOperationContext) Decode(ctx TestDecodeContext, r *http.Request, isList bool, field reflect.Value,
+	tag *Tag) (bool, any, error) {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	v, ok := ctx.(DecodeContextWithContext).Context().Value(testContextKey{}).(string)
+	return ok, v, nil
+}
+
+type testContextKey struct{}
+
+func TestDecodeContextCancel(t *testing.T) {
+	type Inner struct {
+		IVal string `instruct:"ctx"`
+	}
+
+	type DataType struct {
+		Val   string `instruct:"ctx"`
+		Inner Inner  `instruct:"recurse"`
+		Val2  string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("val2", "x2")
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testContextKey{}, "x1"))
+	defer cancel()
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.DecodeOperations["ctx"] = &testDecodeOperationContext{cancel: cancel}
+
+	dc := NewDefaultDecodeContext(DefaultFieldNameMapper)
+	dc.SetContext(ctx)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(&testDecodeContext{
+		DefaultDecodeContext: &dc,
+	}))
+	require.ErrorIs(t, err, context.Canceled)
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Inner"}, ferr.FieldPath)
+	require.Equal(t, "x1", data.Val)
+	require.Equal(t, "", data.Inner.IVal)
+	require.Equal(t, "", data.Val2)
+}
+
+func TestDecodeContextCancelAggregate(t *testing.T) {
+	type Inner struct {
+		IVal string `instruct:"header"`
+	}
+
+	type DataType struct {
+		Val   string `instruct:"header"`
+		Inner Inner  `instruct:"recurse"`
+		Val2  string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dc := NewDefaultDecodeContext(DefaultFieldNameMapper)
+	dc.SetContext(ctx)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	decOpt := GetTestDecoderDecodeOptions(&testDecodeContext{
+		DefaultDecodeContext: &dc,
+	})
+	decOpt.AggregateErrors = ptrTo(true)
+	err := dec.Decode(r, &data, decOpt)
+	var derrs types.DecodeErrors
+	require.ErrorAs(t, err, &derrs)
+	require.Len(t, derrs, 1)
+	require.Equal(t, []string{"Val"}, derrs[0].FieldPath)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDecodeContextValue(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"ctx"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.DecodeOperations["ctx"] = &testDecodeOperationContext{}
+
+	dc := NewDefaultDecodeContext(DefaultFieldNameMapper)
+	dc.SetContext(context.WithValue(context.Background(), testContextKey{}, "x1"))
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(&testDecodeContext{
+		DefaultDecodeContext: &dc,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "x1", data.Val)
+}
+
+func TestDecodeStructList(t *testing.T) {
+	type Tag struct {
+		Name string `instruct:"query"`
+	}
+
+	type Item struct {
+		Name string `instruct:"query"`
+		Qty  int    `instruct:"query,required=false"`
+		Tags []Tag  `instruct:"recurse_list,required=false"`
+	}
+
+	type DataType struct {
+		Items    []Item  `instruct:"recurse_list"`
+		PItems   []*Item `instruct:"recurse_list,required=false"`
+		NotFound []Item  `instruct:"recurse_list,required=false"`
+	}
+
+	q := url.Values{}
+	q.Set("items[0].name", "a")
+	q.Set("items[0].qty", "2")
+	q.Set("items[0].tags[0].name", "t1")
+	q.Set("items[0].tags[3].name", "t2")
+	q.Set("items[4].name", "b")
+	q.Set("pitems[0].name", "p")
+	r := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(&testDecodeContext{
+		ensureAllQueryUsed: true,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, DataType{
+		Items: []Item{
+			{Name: "a", Qty: 2, Tags: []Tag{{Name: "t1"}, {Name: "t2"}}},
+			{Name: "b"},
+		},
+		PItems: []*Item{{Name: "p"}},
+	}, data)
+}
+
+func TestDecodeStructListFormat(t *testing.T) {
+	type Item struct {
+		Name string `instruct:"query"`
+	}
+
+	type DataType struct {
+		Items []Item `instruct:"recurse_list"`
+	}
+
+	for _, tt := range []struct {
+		name      string
+		keyFormat ListKeyFormat
+		query     string
+	}{
+		{"brackets", ListKeyFormatBrackets, "items%5B1%5D.name=b&items%5B0%5D.name=a"},
+		{"dots", ListKeyFormatDots, "items.1.name=b&items.0.name=a"},
+		{"underscores", ListKeyFormatUnderscores, "items_1_name=b&items_0_name=a"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+			defOpt := GetTestDecoderOptions()
+			defOpt.ListKeyFormat = tt.keyFormat
+
+			var data DataType
+
+			dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+			err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+			require.NoError(t, err)
+			require.Equal(t, []Item{{Name: "a"}, {Name: "b"}}, data.Items)
+		})
+	}
+}
+
+func TestDecodeStructListErrors(t *testing.T) {
+	type Item struct {
+		Name string `instruct:"query"`
+		Qty  int    `instruct:"query,required=false"`
+	}
+
+	type DataType struct {
+		Items []Item `instruct:"recurse_list"`
+	}
+
+	q := url.Values{}
+	q.Set("items[0].name", "a")
+	q.Set("items[0].qty", "x")
+	q.Set("items[2].qty", "3")
+	r := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	decOpt := GetTestDecoderDecodeOptions(nil)
+	decOpt.AggregateErrors = ptrTo(true)
+	err := dec.Decode(r, &data, decOpt)
+	var derrs types.DecodeErrors
+	require.ErrorAs(t, err, &derrs)
+	require.Len(t, derrs, 2)
+	require.Equal(t, []string{"Items", "0", "Qty"}, derrs[0].FieldPath)
+	require.Equal(t, "items[0].qty", derrs[0].TagName)
+	require.Equal(t, []string{"Items", "2", "Name"}, derrs[1].FieldPath)
+	require.ErrorAs(t, derrs[1].Err, &types.RequiredError{})
+}
+
+func TestDecodeStructListMaxLength(t *testing.T) {
+	type Item struct {
+		Name string `instruct:"query"`
+	}
+
+	type DataType struct {
+		Items []Item `instruct:"recurse_list"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?items%5B0%5D.name=a&items%5B1%5D.name=b&items%5B2%5D.name=c", nil)
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.ListMaxLength = 2
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.ErrorIs(t, err, types.ErrListMaxLength)
+}
+
+func TestDecodeStructListNoNames(t *testing.T) {
+	type Item struct {
+		Name string `instruct:"header"`
+	}
+
+	type DataType struct {
+		Items []Item `instruct:"recurse_list"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.Error(t, err)
+}
+
+func TestDecodeStructListInvalidType(t *testing.T) {
+	type DataType struct {
+		Items []string `instruct:"recurse_list"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var data DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.Error(t, err)
+}
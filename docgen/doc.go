@@ -0,0 +1,3 @@
+// Package docgen renders reference documentation tables in Markdown or HTML from a
+// [github.com/rrgmc/instruct.Schema].
+package docgen
@@ -0,0 +1,254 @@
+package docgen
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/internal/typeschema"
+	"github.com/rrgmc/instruct/types"
+)
+
+// Default tag option names.
+const (
+	DefaultDescriptionOption = typeschema.DefaultDescriptionOption
+	DefaultExampleOption     = typeschema.DefaultExampleOption
+)
+
+// Row is a documented field.
+type Row struct {
+	Path        string // field path like "Paging.Page", without embedded structs. Lists are shown as "Items[].Name".
+	Operation   string // decode operation.
+	Name        string // external name, like the header or query param name.
+	Type        string // Go type of the field.
+	Required    bool
+	Default     string
+	HasDefault  bool
+	Description string
+	Example     string
+	Options     []TagOption // options declared by the operation, if it implements instruct.DecodeOperationOptionsSpec.
+}
+
+// TagOption is a tag option declared by the operation of a field.
+type TagOption struct {
+	types.TagOptionSpec
+	Value    string // value set on the field tag.
+	HasValue bool   // whether the value was set on the field tag.
+}
+
+// String returns the option in the "name=value (type, default value)" format.
+func (o TagOption) String() string {
+	var b strings.Builder
+	b.WriteString(o.Name)
+	if o.HasValue {
+		fmt.Fprintf(&b, "=%s", o.Value)
+	}
+	b.WriteString(" (")
+	b.WriteString(o.Type.String())
+	if len(o.Values) > 0 {
+		fmt.Fprintf(&b, " %s", strings.Join(o.Values, "/"))
+	}
+	if o.Default != "" {
+		fmt.Fprintf(&b, ", default %s", o.Default)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Generator renders documentation from an [instruct.Schema].
+type Generator struct {
+	fieldOptions  typeschema.FieldOptions
+	listKeyFormat instruct.ListKeyFormat
+}
+
+// NewGenerator creates a new Generator.
+func NewGenerator(options ...Option) *Generator {
+	ret := &Generator{
+		fieldOptions:  typeschema.NewFieldOptions(),
+		listKeyFormat: instruct.ListKeyFormatBrackets,
+	}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+type Option func(*Generator)
+
+// WithDescriptionOption sets the tag option name used for descriptions. Default "desc".
+func WithDescriptionOption(name string) Option {
+	return func(g *Generator) {
+		g.fieldOptions.DescriptionOption = name
+	}
+}
+
+// WithExampleOption sets the tag option name used for examples. Default "example".
+func WithExampleOption(name string) Option {
+	return func(g *Generator) {
+		g.fieldOptions.ExampleOption = name
+	}
+}
+
+// WithListKeyFormat sets the format used to show the names of list element fields. It must be the same as the
+// Decoder one. Default [instruct.ListKeyFormatBrackets].
+func WithListKeyFormat(listKeyFormat instruct.ListKeyFormat) Option {
+	return func(g *Generator) {
+		g.listKeyFormat = listKeyFormat
+	}
+}
+
+// Rows returns the documented fields of the schema, in declaration order.
+// Ignored fields are skipped, and nested "recurse" structs are flattened. The fields of "recurse_list" elements
+// are shown with the name of the first element, like "items[0].name".
+func (g *Generator) Rows(schema *instruct.Schema) []Row {
+	var ret []Row
+	if schema.StructOption != nil {
+		ret = append(ret, g.row(schema.StructOption, instruct.StructOptionMapTag, schema.StructOption.TagName))
+	}
+	return g.rows(ret, schema.Fields, "", nil)
+}
+
+func (g *Generator) rows(ret []Row, fields []*instruct.SchemaField, pathPrefix string,
+	rename func(string) string) []Row {
+	for _, field := range fields {
+		path := pathPrefix + field.Name
+		name := field.TagName
+		if rename != nil {
+			name = rename(name)
+		}
+
+		switch field.Operation {
+		case instruct.OperationIgnore:
+		case instruct.OperationRecurse:
+			// like in the decoder, the name of embedded structs is not part of the path.
+			innerPrefix := path + "."
+			if field.Embedded {
+				innerPrefix = pathPrefix
+			}
+			ret = g.rows(ret, field.Struct.Fields, innerPrefix, rename)
+		case instruct.OperationRecurseList:
+			ret = append(ret, g.row(field, path, name))
+			listName := name
+			ret = g.rows(ret, field.Struct.Fields, path+"[].", func(s string) string {
+				return g.listKeyFormat.Name(listName, 0, s)
+			})
+		default:
+			ret = append(ret, g.row(field, path, name))
+		}
+	}
+	return ret
+}
+
+func (g *Generator) row(field *instruct.SchemaField, path, name string) Row {
+	return Row{
+		Path:        path,
+		Operation:   field.Operation,
+		Name:        name,
+		Type:        field.Type,
+		Required:    field.Required,
+		Default:     field.Default,
+		HasDefault:  field.HasDefault,
+		Description: g.fieldOptions.Description(field),
+		Example:     field.Options[g.fieldOptions.ExampleOption],
+		Options:     fieldOptions(field),
+	}
+}
+
+func fieldOptions(field *instruct.SchemaField) []TagOption {
+	var ret []TagOption
+	for _, spec := range field.OptionsSpec {
+		value, ok := field.Options[spec.Name]
+		ret = append(ret, TagOption{TagOptionSpec: spec, Value: value, HasValue: ok})
+	}
+	return ret
+}
+
+// Markdown writes a Markdown section with a table for each schema.
+func (g *Generator) Markdown(w io.Writer, schemas ...*instruct.Schema) error {
+	for i, schema := range schemas {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "## %s\n\n", schema.Type)
+		b.WriteString("| Field | Operation | Name | Type | Required | Default | Description | Example | Options |\n")
+		b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+		for _, row := range g.Rows(schema) {
+			var options []string
+			for _, option := range row.Options {
+				options = append(options, markdownCode(option.String()))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownCode(row.Path), markdownEscape(row.Operation), markdownCode(row.Name), markdownCode(row.Type),
+				yesNo(row.Required), markdownDefault(row), markdownEscape(row.Description),
+				markdownOptionalCode(row.Example), strings.Join(options, "<br>"))
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var htmlTemplate = template.Must(template.New("docgen").Parse(`{{range .}}<h2>{{.Type}}</h2>
+<table>
+<thead>
+<tr><th>Field</th><th>Operation</th><th>Name</th><th>Type</th><th>Required</th><th>Default</th><th>Description</th><th>Example</th><th>Options</th></tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr><td><code>{{.Path}}</code></td><td>{{.Operation}}</td><td><code>{{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{if .HasDefault}}<code>{{.Default}}</code>{{end}}</td><td>{{.Description}}</td><td>{{if .Example}}<code>{{.Example}}</code>{{end}}</td><td>{{range $i, $o := .Options}}{{if $i}}<br>{{end}}<code>{{$o}}</code>{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}`))
+
+// HTML writes an HTML section with a table for each schema. All values are escaped.
+func (g *Generator) HTML(w io.Writer, schemas ...*instruct.Schema) error {
+	type htmlSchema struct {
+		Type string
+		Rows []Row
+	}
+	var data []htmlSchema
+	for _, schema := range schemas {
+		data = append(data, htmlSchema{Type: schema.Type, Rows: g.Rows(schema)})
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func markdownDefault(row Row) string {
+	if !row.HasDefault {
+		return ""
+	}
+	return markdownCode(row.Default)
+}
+
+func markdownOptionalCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return markdownCode(s)
+}
+
+// markdownCode returns the value as a code span, escaping pipes so the table is not broken.
+func markdownCode(s string) string {
+	s = strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// markdownEscape escapes the characters that would break a table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,131 @@
+package docgen
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testPaging struct {
+	Page  int `instruct:"query,required=false,default=1"`
+	Limit int `instruct:"query,desc=Page size,example=20"`
+}
+
+type testItem struct {
+	SKU string `instruct:"query,desc=Item code"`
+}
+
+type testRequest struct {
+	Token  string     `instruct:"header,name=X-Token,desc=Token | bearer"`
+	Paging testPaging `instruct:"recurse"`
+	Items  []testItem `instruct:"recurse_list,required=false"`
+	Ctx    string     `instruct:"-"`
+}
+
+func TestMarkdown(t *testing.T) {
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.SchemaWithMapTags(reflect.TypeOf(testRequest{}), instruct.MapTags{
+		"Paging": instruct.MapTags{
+			"Limit": "query,name=size,required=false,desc=Page size",
+		},
+	})
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, NewGenerator().Markdown(&b, schema))
+	require.Equal(t, "## docgen.testRequest\n"+
+		"\n"+
+		"| Field | Operation | Name | Type | Required | Default | Description | Example | Options |\n"+
+		"|---|---|---|---|---|---|---|---|---|\n"+
+		"| `Token` | header | `X-Token` | `string` | yes |  | Token \\| bearer |  |  |\n"+
+		"| `Paging.Page` | query | `page` | `int` | no | `1` |  |  |  |\n"+
+		"| `Paging.Limit` | query | `size` | `int` | no |  | Page size |  |  |\n"+
+		"| `Items` | recurse_list | `items` | `[]docgen.testItem` | no |  |  |  |  |\n"+
+		"| `Items[].SKU` | query | `items[0].sku` | `string` | yes |  | Item code |  |  |\n",
+		b.String())
+}
+
+func TestHTML(t *testing.T) {
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(testPaging{}))
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, NewGenerator(WithExampleOption("example")).HTML(&b, schema))
+	require.Contains(t, b.String(), "<h2>docgen.testPaging</h2>")
+	require.Contains(t, b.String(), "<tr><td><code>Page</code></td><td>query</td><td><code>page</code></td>"+
+		"<td><code>int</code></td><td>no</td><td><code>1</code></td><td></td><td></td><td></td></tr>")
+	require.Contains(t, b.String(), "<tr><td><code>Limit</code></td><td>query</td><td><code>limit</code></td>"+
+		"<td><code>int</code></td><td>yes</td><td></td><td>Page size</td><td><code>20</code></td><td></td></tr>")
+}
+
+type testQueryOperation struct{}
+
+func (testQueryOperation) Decode(ctx instruct.DecodeContext, input *http.Request, isList bool,
+	field reflect.Value, tag *instruct.Tag) (bool, any, error) {
+	return false, nil, nil
+}
+
+func (testQueryOperation) OptionsSpec() []types.TagOptionSpec {
+	return []types.TagOptionSpec{
+		{Name: "explode", Type: types.TagOptionBool, Default: "true"},
+		{Name: "style", Type: types.TagOptionEnum, Values: []string{"form", "pipe"}},
+	}
+}
+
+func TestMarkdownOptions(t *testing.T) {
+	type Request struct {
+		IDs []int `instruct:"query,explode=false"`
+	}
+
+	opt := instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]()
+	opt.DecodeOperations["query"] = testQueryOperation{}
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](opt)
+
+	schema, err := dec.Schema(reflect.TypeOf(Request{}))
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, NewGenerator().Markdown(&b, schema))
+	require.Contains(t, b.String(), "| `IDs` | query | `ids` | `[]int` | yes |  |  |  | "+
+		"`explode=false (bool, default true)`<br>`style (enum form/pipe)` |\n")
+
+	b.Reset()
+	require.NoError(t, NewGenerator().HTML(&b, schema))
+	require.Contains(t, b.String(), "<td><code>explode=false (bool, default true)</code><br>"+
+		"<code>style (enum form/pipe)</code></td></tr>")
+}
+
+// Common is exported to be embedded.
+type Common struct {
+	RequestID string `instruct:"header"`
+}
+
+func TestRowsEmbedded(t *testing.T) {
+	type DataType struct {
+		Common
+		Paging testPaging `instruct:"recurse"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(DataType{}))
+	require.NoError(t, err)
+
+	// the embedded struct name is not part of the path, like in the decoder.
+	var paths []string
+	for _, row := range NewGenerator().Rows(schema) {
+		paths = append(paths, row.Path)
+	}
+	require.Equal(t, []string{"RequestID", "Paging.Page", "Paging.Limit"}, paths)
+}
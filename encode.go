@@ -0,0 +1,61 @@
+package instruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Encoder encodes structs to outputs. It is the inverse of Decoder, and uses the same struct tags and MapTags.
+type Encoder[OT any, EC EncodeContext] struct {
+	options DefaultEncodeOptions[OT, EC]
+}
+
+// NewEncoder creates an Encoder instance without any encode operations. At least one must be added for
+// encoding to work.
+func NewEncoder[OT any, EC EncodeContext](options DefaultEncodeOptions[OT, EC]) *Encoder[OT, EC] {
+	ret := &Encoder[OT, EC]{
+		options: options,
+	}
+	return ret
+}
+
+// Encode encodes the struct passed in "data" to the output. "data" may be a struct or a pointer to a struct.
+func (e *Encoder[OT, EC]) Encode(output OT, data any, encodeOptions EncodeOptions[OT, EC]) error {
+	if isZero(encodeOptions.Ctx) {
+		return errors.New("encode context cannot be nil")
+	}
+
+	si, err := e.structInfoFromType(reflect.TypeOf(data))
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(data)
+	if !reflectValueElem(rv).IsValid() {
+		return fmt.Errorf("cannot encode from nil %s", rv.Type().String())
+	}
+
+	if encodeOptions.MapTags != nil {
+		// if there is a encode-specific map tag, create a new struct info based on the default one.
+		si, err = structInfoWithMapTags(si, encodeOptions.MapTags, e.options.structInfoOptions())
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.encodeStruct(si, output, rv, encodeOptions)
+}
+
+// structInfoFromType builds an structInfo from a [reflect.Type], using the encoder options.
+func (e *Encoder[OT, EC]) structInfoFromType(typ reflect.Type) (*structInfo, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("cannot encode from nil")
+	}
+	typ = reflectElem(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only encode from struct, received: %v", typ.Kind())
+	}
+
+	return e.options.structInfoProvider.provide(typ, e.options.defaultMapTags.Get(typ), e.options.structInfoOptions())
+}
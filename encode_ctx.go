@@ -0,0 +1,23 @@
+package instruct
+
+// EncodeContext is the context sent to EncodeOperation.
+type EncodeContext interface {
+	// FieldNameMapper returns the FieldNameMapper instance to be used for converting the struct field name.
+	FieldNameMapper() FieldNameMapper
+}
+
+// DefaultEncodeContext implements the standard encode context.
+type DefaultEncodeContext struct {
+	fieldNameMapper FieldNameMapper
+}
+
+// NewDefaultEncodeContext creates an instance of DefaultEncodeContext.
+func NewDefaultEncodeContext(fnMapper FieldNameMapper) DefaultEncodeContext {
+	return DefaultEncodeContext{
+		fieldNameMapper: fnMapper,
+	}
+}
+
+func (d *DefaultEncodeContext) FieldNameMapper() FieldNameMapper {
+	return d.fieldNameMapper
+}
@@ -0,0 +1,13 @@
+package instruct
+
+import (
+	"reflect"
+)
+
+// EncodeOperation is the interface for the struct-to-output encoders. It is the inverse of DecodeOperation.
+type EncodeOperation[OT any, EC EncodeContext] interface {
+	// Encode encodes a field value to the output. "value" is the field value converted by the Formatter, usually
+	// a string, or a []string if isList is true. If the operation needs the raw field value, for example when
+	// encoding a struct field into a JSON HTTP body, use the "field" parameter directly.
+	Encode(ctx EC, output OT, isList bool, field reflect.Value, value any, tag *Tag) error
+}
@@ -0,0 +1,67 @@
+package instruct
+
+import (
+	"reflect"
+
+	"github.com/rrgmc/instruct/formatter"
+)
+
+type DefaultEncodeOptions[OT any, EC EncodeContext] struct {
+	TagName            string                             // struct tag name. Default "instruct".
+	DefaultRequired    bool                               // whether the default for fields should be "required" or "not required"
+	EncodeOperations   map[string]EncodeOperation[OT, EC] // list of encode operations
+	defaultMapTags     *mapTagsList                       // list of DEFAULT map tags
+	FieldNameMapper    FieldNameMapper                    // field name mapper. Default one uses [strings.ToLower].
+	NameFromTags       []string                           // other struct tags to get the name from if the "name" option is not set, like "json". Checked in order before FieldNameMapper.
+	structInfoProvider structInfoProvider                 // allows caching of structInfo
+	Formatter          Formatter                          // interface used to convert the struct field values to strings.
+	ListKeyFormat      ListKeyFormat                      // format of the names of list elements for "recurse_list". Default ListKeyFormatBrackets.
+}
+
+func (o *DefaultEncodeOptions[OT, EC]) DefaultMapTagsSet(t reflect.Type, m MapTags) {
+	t = reflectElem(t)
+	o.defaultMapTags.Set(t, m)
+	o.structInfoProvider.remove(t)
+}
+
+// structInfoOptions returns the options used to build a structInfo.
+func (o *DefaultEncodeOptions[OT, EC]) structInfoOptions() structInfoOptions {
+	return structInfoOptions{
+		TagName:         o.TagName,
+		DefaultRequired: o.DefaultRequired,
+		FieldNameMapper: o.FieldNameMapper,
+		NameFromTags:    o.NameFromTags,
+	}
+}
+
+func (o *DefaultEncodeOptions[OT, EC]) StructInfoCache(cache bool) {
+	if cache {
+		o.structInfoProvider = &cachedStructInfoProvider{}
+	} else {
+		o.structInfoProvider = &defaultStructInfoProvider{}
+	}
+}
+
+type EncodeOptions[OT any, EC EncodeContext] struct {
+	Ctx     EC      // encode context to be sent to EncodeOperation.
+	MapTags MapTags // encode call-specific MapTags. They may override existing ones.
+}
+
+// NewDefaultEncodeOptions returns a DefaultEncodeOptions with the default values.
+func NewDefaultEncodeOptions[OT any, EC EncodeContext]() DefaultEncodeOptions[OT, EC] {
+	return DefaultEncodeOptions[OT, EC]{
+		TagName:            "instruct",
+		DefaultRequired:    true,
+		EncodeOperations:   map[string]EncodeOperation[OT, EC]{},
+		defaultMapTags:     &mapTagsList{},
+		FieldNameMapper:    DefaultFieldNameMapper,
+		structInfoProvider: defaultStructInfoProvider{},
+		Formatter:          formatter.NewFormatter(),
+		ListKeyFormat:      ListKeyFormatBrackets,
+	}
+}
+
+// NewEncodeOptions returns a EncodeOptions with the default values.
+func NewEncodeOptions[OT any, EC EncodeContext]() EncodeOptions[OT, EC] {
+	return EncodeOptions[OT, EC]{}
+}
@@ -0,0 +1,143 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// encodeStruct uses the structInfo to encode the struct to the output.
+func (e *Encoder[OT, EC]) encodeStruct(si *structInfo, output OT, dataValue reflect.Value, encodeOptions EncodeOptions[OT, EC]) error {
+	dataValue = reflectValueElem(dataValue)
+	if err := si.checkSameType(dataValue.Type()); err != nil {
+		return err
+	}
+
+	// execute the struct operation (using StructOption or inner struct tags). Only executed if "when" is
+	// configured as "before".
+	err := e.executeStructOperation(SOOptionWhenBefore, dataValue, si, output, encodeOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, sifield := range si.fields {
+		fieldValue := dataValue.FieldByIndex(sifield.field.Index)
+
+		switch sifield.tag.Operation {
+		case OperationIgnore: // ignore
+		case OperationRecurse:
+			// recurse into inner struct, if it is set
+			if !reflectValueElem(fieldValue).IsValid() {
+				continue
+			}
+			if err := e.encodeStruct(sifield, output, fieldValue, encodeOptions); err != nil {
+				return err
+			}
+		case OperationRecurseList:
+			// encode each element of the slice using indexed names
+			if err := e.encodeStructList(sifield, output, fieldValue, encodeOptions); err != nil {
+				return err
+			}
+		default:
+			// execute operation (query, header, etc.)
+			if err := e.executeOperation(fieldValue, sifield, output, encodeOptions); err != nil {
+				return err
+			}
+		}
+	}
+
+	// execute the struct operation (using StructOption or inner struct tags). Only executed if "when" is
+	// configured as "after".
+	return e.executeStructOperation(SOOptionWhenAfter, dataValue, si, output, encodeOptions)
+}
+
+// encodeStructList encodes each element of a slice of structs for the "recurse_list" operation.
+// Nil elements are skipped.
+func (e *Encoder[OT, EC]) encodeStructList(sifield *structInfo, output OT, fieldValue reflect.Value,
+	encodeOptions EncodeOptions[OT, EC]) error {
+	if fieldValue.IsNil() {
+		if sifield.tag.Required {
+			return newFieldError(sifield, nil, types.RequiredError{
+				Operation: sifield.tag.Operation,
+				FieldName: sifield.fullFieldName(),
+				TagName:   sifield.tag.Name,
+			})
+		}
+		return nil
+	}
+
+	keyFormat := e.options.ListKeyFormat
+	if keyFormat == nil {
+		keyFormat = ListKeyFormatBrackets
+	}
+
+	for i := 0; i < fieldValue.Len(); i++ {
+		elemValue := fieldValue.Index(i)
+		if !reflectValueElem(elemValue).IsValid() {
+			continue
+		}
+		elemsi := sifield.elem.withListIndex(len(sifield.path), sifield.tag.Name, i, keyFormat, true)
+		if err := e.encodeStruct(elemsi, output, elemValue, encodeOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeStructOperation execute the struct operation (using StructOption or inner struct tags).
+func (e *Encoder[OT, EC]) executeStructOperation(when string, dataValue reflect.Value, si *structInfo,
+	output OT, encodeOptions EncodeOptions[OT, EC]) error {
+	if !si.isStructOperation(when) {
+		return nil
+	}
+
+	return e.executeOperation(dataValue, si, output, encodeOptions)
+}
+
+// executeOperation executes an operation (query, header, etc) on a struct field.
+// Nil pointers, slices and maps are not encoded, and return a [types.RequiredError] if the field is required.
+// All returned errors are of type [types.FieldError].
+func (e *Encoder[OT, EC]) executeOperation(field reflect.Value, sifield *structInfo, output OT,
+	encodeOptions EncodeOptions[OT, EC]) error {
+	// check if the operation exists
+	operation, opok := e.options.EncodeOperations[sifield.tag.Operation]
+	if !opok {
+		return newFieldError(sifield, nil, fmt.Errorf("%w '%s'", types.ErrUnknownOperation, sifield.tag.Operation))
+	}
+
+	elemField := reflectValueElem(field)
+	if !elemField.IsValid() || ((elemField.Kind() == reflect.Slice || elemField.Kind() == reflect.Map) && elemField.IsNil()) {
+		if sifield.tag.Required {
+			return newFieldError(sifield, nil, types.RequiredError{
+				IsStructOption: sifield.tag.IsSO,
+				Operation:      sifield.tag.Operation,
+				FieldName:      structFieldName(sifield.typ, sifield.fullFieldName()),
+				TagName:        sifield.tag.Name,
+			})
+		}
+		return nil
+	}
+
+	// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+	isPrimitive := elemField.Type().PkgPath() == ""
+	isList := isPrimitive && (elemField.Kind() == reflect.Slice || elemField.Kind() == reflect.Array)
+
+	var value any
+	var err error
+	if fo, ok := e.options.Formatter.(FormatterWithOptions); ok {
+		value, err = fo.FormatOptions(field, &sifield.tag.Options)
+	} else {
+		value, err = e.options.Formatter.Format(field)
+	}
+	if err != nil {
+		return newFieldError(sifield, nil, err)
+	}
+
+	// call the encoder interface.
+	if err = operation.Encode(encodeOptions.Ctx, output, isList, field, value, sifield.tag); err != nil {
+		return newFieldError(sifield, value, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+package instruct
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rrgmc/instruct/formatter"
+	"github.com/rrgmc/instruct/resolver"
+	"github.com/rrgmc/instruct/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	type DTestEmbed struct {
+		H string `instruct:"header"`
+		Q string `instruct:"query"`
+	}
+
+	type DTest1 struct {
+		Q int `instruct:"query,name=Q1"`
+	}
+
+	type DTestBody struct {
+		F1 string
+		F2 int
+	}
+
+	type DTest struct {
+		DTestEmbed
+		T1  DTest1    `instruct:"recurse"`
+		TB  DTestBody `instruct:"body"`
+		L   []int32   `instruct:"query"`
+		LH  []string  `instruct:"header"`
+		P   *float64  `instruct:"header"`
+		B   bool      `instruct:"query"`
+		Ign string    `instruct:"-"`
+	}
+
+	pv := 12.5
+
+	data := &DTest{
+		DTestEmbed: DTestEmbed{
+			H: "ValueH",
+			Q: "ValueQ",
+		},
+		T1: DTest1{
+			Q: 66,
+		},
+		TB: DTestBody{
+			F1: "ValueF1",
+			F2: 99,
+		},
+		L:   []int32{5, 6, 7},
+		LH:  []string{"a", "b"},
+		P:   &pv,
+		B:   true,
+		Ign: "ignored",
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+
+	require.Equal(t, "ValueH", r.Header.Get("h"))
+	require.Equal(t, "ValueQ", r.URL.Query().Get("q"))
+	require.Equal(t, "66", r.URL.Query().Get("Q1"))
+	require.Equal(t, "5,6,7", r.URL.Query().Get("l"))
+	require.Equal(t, []string{"a", "b"}, r.Header.Values("lh"))
+	require.Equal(t, "12.5", r.Header.Get("p"))
+	require.Equal(t, "true", r.URL.Query().Get("b"))
+	require.False(t, r.URL.Query().Has("ign"))
+
+	// round trip
+	var decoded DTest
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(&testDecodeContext{
+		allowReadBody: true,
+	}))
+	require.NoError(t, err)
+
+	data.Ign = ""
+	require.Equal(t, data, &decoded)
+}
+
+func TestEncodeNonPointer(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, DataType{Val: "x1"}, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, "x1", r.Header.Get("val"))
+}
+
+func TestEncodeNilPointer(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var data *DataType
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.Error(t, err)
+}
+
+func TestEncodeNoContext(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"header"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	encOpt := GetTestEncoderEncodeOptions(nil)
+	encOpt.Ctx = nil
+	err := enc.Encode(r, &DataType{}, encOpt)
+	require.Error(t, err)
+}
+
+func TestEncodeRequiredError(t *testing.T) {
+	type DataType struct {
+		Val  *string `instruct:"header"`
+		Val2 *string `instruct:"header,required=false"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, &DataType{}, GetTestEncoderEncodeOptions(nil))
+	var reqErr types.RequiredError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, "Val", reqErr.FieldName)
+
+	var ferr *types.FieldError
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"Val"}, ferr.FieldPath)
+}
+
+func TestEncodeUnknownOperation(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"invalid_operation"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, &DataType{}, GetTestEncoderEncodeOptions(nil))
+	require.ErrorIs(t, err, types.ErrUnknownOperation)
+}
+
+func TestEncodeStructOption(t *testing.T) {
+	type Inner struct {
+		_       StructOption `instruct:"body,type=xml"`
+		XMLName xml.Name     `instruct:"-" xml:"Inner"`
+		Val     string
+	}
+
+	type DataType struct {
+		I Inner
+		H string `instruct:"header"`
+	}
+
+	data := &DataType{
+		I: Inner{Val: "15"},
+		H: "x1",
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+
+	var decoded DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(&testDecodeContext{
+		allowReadBody: true,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "15", decoded.I.Val)
+	require.Equal(t, "x1", decoded.H)
+}
+
+func TestEncodeMapTags(t *testing.T) {
+	type DataType struct {
+		Val string
+		X   struct {
+			X1 string
+		}
+	}
+
+	mapTags := MapTags{
+		"Val": "header",
+		"X": MapTags{
+			"X1": "query",
+		},
+	}
+
+	data := &DataType{Val: "x1"}
+	data.X.X1 = "x2"
+
+	encOpt := GetTestEncoderOptions()
+	encOpt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), mapTags)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](encOpt)
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, "x1", r.Header.Get("val"))
+	require.Equal(t, "x2", r.URL.Query().Get("x1"))
+
+	// round trip
+	var decoded DataType
+
+	decOpt := GetTestDecoderOptions()
+	decOpt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), mapTags)
+	dec := NewDecoder[*http.Request, TestDecodeContext](decOpt)
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, data, &decoded)
+}
+
+func TestEncodeMapTagsEncodeOverride(t *testing.T) {
+	type DataType struct {
+		Val string `instruct:"query"`
+		X   struct {
+			X1 string `instruct:"query"`
+		} `instruct:"recurse"`
+	}
+
+	data := &DataType{Val: "x1"}
+	data.X.X1 = "x2"
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	encOpt := GetTestEncoderEncodeOptions(nil)
+	encOpt.MapTags = MapTags{
+		"X": MapTags{
+			"X1": "header",
+		},
+	}
+	err := enc.Encode(r, data, encOpt)
+	require.NoError(t, err)
+	require.Equal(t, "x1", r.URL.Query().Get("val"))
+	require.Equal(t, "x2", r.Header.Get("x1"))
+	require.False(t, r.URL.Query().Has("x1"))
+}
+
+func TestEncodeCustomFormatter(t *testing.T) {
+	type DataType struct {
+		Val time.Time     `instruct:"query"`
+		Dur time.Duration `instruct:"header"`
+	}
+
+	t1, _ := time.Parse(time.RFC3339, "2021-10-22T11:01:00Z")
+
+	data := &DataType{
+		Val: t1,
+		Dur: 5 * time.Second,
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	encOpt := GetTestEncoderOptions()
+	encOpt.Formatter = formatter.NewFormatter(formatter.WithValueFormatter(formatter.NewDefaultValueFormatter(
+		formatter.WithCustomTypes(
+			formatter.NewValueFormatterTime(time.RFC3339),
+			formatter.NewValueFormatterTimeDuration(),
+		))))
+	enc := NewEncoder[*http.Request, TestEncodeContext](encOpt)
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+
+	// round trip
+	var decoded DataType
+
+	decOpt := GetTestDecoderOptions()
+	decOpt.Resolver = resolver.NewResolver(resolver.WithValueResolver(resolver.NewDefaultValueResolver(
+		resolver.WithCustomTypes(
+			resolver.NewValueResolverTime(time.RFC3339),
+			resolver.NewValueResolverTimeDuration(),
+		))))
+	dec := NewDecoder[*http.Request, TestDecodeContext](decOpt)
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, data, &decoded)
+}
+
+func TestEncodeStructList(t *testing.T) {
+	type Item struct {
+		Name string `instruct:"query"`
+		Qty  int    `instruct:"query"`
+	}
+
+	type DataType struct {
+		Items []Item `instruct:"recurse_list"`
+	}
+
+	data := &DataType{
+		Items: []Item{{Name: "a", Qty: 1}, {Name: "b", Qty: 2}},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+
+	require.Equal(t, "a", r.URL.Query().Get("items[0].name"))
+	require.Equal(t, "2", r.URL.Query().Get("items[1].qty"))
+
+	// round trip
+	var decoded DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, data, &decoded)
+}
+
+func TestEncodeMapField(t *testing.T) {
+	type DataType struct {
+		Val      map[string]string   `instruct:"query"`
+		ValInt   map[string]int      `instruct:"query,explodesep=;,mapsep=;,mapkvsep=:"`
+		ValSlice map[string][]string `instruct:"header"`
+		ValNil   map[string]string   `instruct:"query,required=false"`
+	}
+
+	data := &DataType{
+		Val:      map[string]string{"k2": "v2", "k1": "v1"},
+		ValInt:   map[string]int{"b": 2, "a": 1},
+		ValSlice: map[string][]string{"k1": {"a", "b"}, "k2": {"c"}},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	enc := NewEncoder[*http.Request, TestEncodeContext](GetTestEncoderOptions())
+	err := enc.Encode(r, data, GetTestEncoderEncodeOptions(nil))
+	require.NoError(t, err)
+
+	require.Equal(t, "k1=v1,k2=v2", r.URL.Query().Get("val"))
+	require.Equal(t, "a:1;b:2", r.URL.Query().Get("valint"))
+	require.Equal(t, "k1=a,k1=b,k2=c", r.Header.Get("valslice"))
+	require.False(t, r.URL.Query().Has("valnil"))
+
+	// round trip
+	var decoded DataType
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err = dec.Decode(r, &decoded, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, data, &decoded)
+}
@@ -0,0 +1,131 @@
+package instruct
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCaseFieldNameMapper converts names to snake_case, like "UserID" to "user_id".
+func SnakeCaseFieldNameMapper(operation string, name string) string {
+	return joinFieldNameWords(splitFieldNameWords(name), "_", strings.ToLower)
+}
+
+// KebabCaseFieldNameMapper converts names to kebab-case, like "UserID" to "user-id".
+func KebabCaseFieldNameMapper(operation string, name string) string {
+	return joinFieldNameWords(splitFieldNameWords(name), "-", strings.ToLower)
+}
+
+// ScreamingSnakeCaseFieldNameMapper converts names to SCREAMING_SNAKE_CASE, like "UserID" to "USER_ID".
+func ScreamingSnakeCaseFieldNameMapper(operation string, name string) string {
+	return joinFieldNameWords(splitFieldNameWords(name), "_", strings.ToUpper)
+}
+
+// CamelCaseFieldNameMapper converts names to camelCase, like "UserID" to "userId".
+func CamelCaseFieldNameMapper(operation string, name string) string {
+	words := splitFieldNameWords(name)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = titleWord(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// HTTPHeaderFieldNameMapper converts names to the canonical HTTP header format, like "XRequestID" to
+// "X-Request-Id".
+func HTTPHeaderFieldNameMapper(operation string, name string) string {
+	return joinFieldNameWords(splitFieldNameWords(name), "-", titleWord)
+}
+
+// PrefixFieldNameMapper returns a FieldNameMapper that adds a prefix to the names returned by mapper.
+func PrefixFieldNameMapper(prefix string, mapper FieldNameMapper) FieldNameMapper {
+	return func(operation string, name string) string {
+		return prefix + mapper(operation, name)
+	}
+}
+
+// SuffixFieldNameMapper returns a FieldNameMapper that adds a suffix to the names returned by mapper.
+func SuffixFieldNameMapper(suffix string, mapper FieldNameMapper) FieldNameMapper {
+	return func(operation string, name string) string {
+		return mapper(operation, name) + suffix
+	}
+}
+
+// OperationFieldNameMapper returns a FieldNameMapper that selects the mapper by operation. Operations not in
+// the map use defaultMapper, or [DefaultFieldNameMapper] if it is nil.
+func OperationFieldNameMapper(mappers map[string]FieldNameMapper, defaultMapper FieldNameMapper) FieldNameMapper {
+	if defaultMapper == nil {
+		defaultMapper = DefaultFieldNameMapper
+	}
+	return func(operation string, name string) string {
+		if mapper, ok := mappers[operation]; ok {
+			return mapper(operation, name)
+		}
+		return defaultMapper(operation, name)
+	}
+}
+
+// splitFieldNameWords splits a Go field name into words. Acronyms are kept as a single word, including plurals,
+// so "UserIDs" is split into "User" and "IDs", and "HTTPServer" into "HTTP" and "Server". Digits are kept with
+// the previous word. Underscores, dashes and other separators also split words.
+func splitFieldNameWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				// "userId" or "v2Beta"
+				flush(i)
+				start = i
+			} else if unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) &&
+				!isAcronymPlural(runes, i) {
+				// "HTTPServer"
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// isAcronymPlural returns whether the upper case rune at i is followed by a plural "s" ending the word, like the
+// "D" in "IDs".
+func isAcronymPlural(runes []rune, i int) bool {
+	return runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2]))
+}
+
+func joinFieldNameWords(words []string, sep string, f func(string) string) string {
+	for i, word := range words {
+		words[i] = f(word)
+	}
+	return strings.Join(words, sep)
+}
+
+// titleWord returns the word with the first letter in upper case and the others in lower case.
+func titleWord(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
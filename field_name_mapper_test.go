@@ -0,0 +1,80 @@
+package instruct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldNameMappers(t *testing.T) {
+	tests := []struct {
+		name           string
+		snake          string
+		kebab          string
+		screamingSnake string
+		camel          string
+		httpHeader     string
+	}{
+		{"UserID", "user_id", "user-id", "USER_ID", "userId", "User-Id"},
+		{"ID", "id", "id", "ID", "id", "Id"},
+		{"UserIDs", "user_ids", "user-ids", "USER_IDS", "userIds", "User-Ids"},
+		{"UserIDsList", "user_ids_list", "user-ids-list", "USER_IDS_LIST", "userIdsList", "User-Ids-List"},
+		{"HTTPServer", "http_server", "http-server", "HTTP_SERVER", "httpServer", "Http-Server"},
+		{"XRequestID", "x_request_id", "x-request-id", "X_REQUEST_ID", "xRequestId", "X-Request-Id"},
+		{"Address2Line", "address2_line", "address2-line", "ADDRESS2_LINE", "address2Line", "Address2-Line"},
+		{"already_snake", "already_snake", "already-snake", "ALREADY_SNAKE", "alreadySnake", "Already-Snake"},
+		{"name", "name", "name", "NAME", "name", "Name"},
+		{"ÜberName", "über_name", "über-name", "ÜBER_NAME", "überName", "Über-Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.snake, SnakeCaseFieldNameMapper("query", tt.name))
+			require.Equal(t, tt.kebab, KebabCaseFieldNameMapper("query", tt.name))
+			require.Equal(t, tt.screamingSnake, ScreamingSnakeCaseFieldNameMapper("query", tt.name))
+			require.Equal(t, tt.camel, CamelCaseFieldNameMapper("query", tt.name))
+			require.Equal(t, tt.httpHeader, HTTPHeaderFieldNameMapper("header", tt.name))
+		})
+	}
+}
+
+func TestFieldNameMapperWrappers(t *testing.T) {
+	mapper := OperationFieldNameMapper(map[string]FieldNameMapper{
+		"query":  SnakeCaseFieldNameMapper,
+		"header": PrefixFieldNameMapper("X-", HTTPHeaderFieldNameMapper),
+		"env":    SuffixFieldNameMapper("_FILE", ScreamingSnakeCaseFieldNameMapper),
+	}, nil)
+
+	require.Equal(t, "user_id", mapper("query", "UserID"))
+	require.Equal(t, "X-User-Id", mapper("header", "UserID"))
+	require.Equal(t, "USER_ID_FILE", mapper("env", "UserID"))
+	require.Equal(t, "userid", mapper("form", "UserID"))
+
+	mapper = OperationFieldNameMapper(nil, KebabCaseFieldNameMapper)
+	require.Equal(t, "user-id", mapper("form", "UserID"))
+}
+
+func TestFieldNameMapperDecode(t *testing.T) {
+	type DataType struct {
+		UserID  string `instruct:"query"`
+		TraceID string `instruct:"header"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.FieldNameMapper = OperationFieldNameMapper(map[string]FieldNameMapper{
+		TestOperationQuery:  SnakeCaseFieldNameMapper,
+		TestOperationHeader: HTTPHeaderFieldNameMapper,
+	}, nil)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	r := httptest.NewRequest(http.MethodGet, "/?user_id=12", nil)
+	r.Header.Set("Trace-Id", "abc")
+
+	var data DataType
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, "12", data.UserID)
+	require.Equal(t, "abc", data.TraceID)
+}
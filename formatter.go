@@ -0,0 +1,18 @@
+package instruct
+
+import (
+	"reflect"
+
+	"github.com/rrgmc/instruct/resolver"
+)
+
+// Formatter converts struct field values to the values sent to EncodeOperation. It is the inverse of Resolver.
+type Formatter interface {
+	Format(source reflect.Value) (any, error)
+}
+
+// FormatterWithOptions is an optional Formatter extension that receives the field tag options, for example to
+// configure map separators. [formatter.Formatter] implements it.
+type FormatterWithOptions interface {
+	FormatOptions(source reflect.Value, options resolver.Options) (any, error)
+}
@@ -0,0 +1,2 @@
+// Package formatter contains the default implementation of [github.com/rrgmc/instruct.Formatter].
+package formatter
@@ -0,0 +1,145 @@
+package formatter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rrgmc/instruct/resolver"
+)
+
+// Formatter is the default Formatter.
+type Formatter struct {
+	valueFormatter ValueFormatter
+}
+
+// NewFormatter creates a new default Formatter.
+// If not ValueFormatter was set, a default one that suports only primitive types is used.
+func NewFormatter(options ...Option) *Formatter {
+	ret := &Formatter{}
+	for _, opt := range options {
+		opt(ret)
+	}
+	if ret.valueFormatter == nil {
+		ret.valueFormatter = NewDefaultValueFormatter()
+	}
+	return ret
+}
+
+type Option func(formatter *Formatter)
+
+// WithValueFormatter sets a custom ValueFormatter to be used instead of the default.
+func WithValueFormatter(valueFormatter ValueFormatter) Option {
+	return func(r *Formatter) {
+		r.valueFormatter = valueFormatter
+	}
+}
+
+// Format converts the source value to a string, or to a []string if it is a slice or array.
+// Values of unsupported types are returned unchanged.
+// A nil pointer returns a nil value.
+func (r Formatter) Format(source reflect.Value) (any, error) {
+	return r.FormatOptions(source, nil)
+}
+
+// FormatOptions formats the value using per-field options. "options" may be nil.
+// Maps are formatted as a string in the "k1=v1,k2=v2" format, sorted by key, using the same separator options as
+// [resolver.Resolver]. If the map value is a slice, each item is formatted as a repeated key.
+func (r Formatter) FormatOptions(source reflect.Value, options resolver.Options) (any, error) {
+	// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+	isPrimitive := source.Type().PkgPath() == ""
+	if isPrimitive && (source.Kind() == reflect.Slice || source.Kind() == reflect.Array) {
+		if source.Kind() == reflect.Slice && source.IsNil() {
+			return nil, nil
+		}
+		ret := make([]string, 0, source.Len())
+		for i := 0; i < source.Len(); i++ {
+			s, err := r.formatString(source.Index(i), options, "list item")
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, s)
+		}
+		return ret, nil
+	} else if isPrimitive && source.Kind() == reflect.Map {
+		if source.IsNil() {
+			return nil, nil
+		}
+		return r.formatMap(source, options)
+	} else if source.Kind() == reflect.Pointer {
+		if source.IsNil() {
+			return nil, nil
+		}
+		return r.FormatOptions(source.Elem(), options)
+	}
+
+	return r.valueFormatter.FormatValue(source)
+}
+
+// formatMap formats a map as a string, the inverse of the [resolver.Resolver] map parsing.
+func (r Formatter) formatMap(source reflect.Value, options resolver.Options) (any, error) {
+	entrySeparator := optionValue(options, resolver.OptionMapSeparator, resolver.DefaultMapSeparator)
+	kvSeparator := optionValue(options, resolver.OptionMapKeyValueSeparator, resolver.DefaultMapKeyValueSeparator)
+
+	type mapEntry struct {
+		key    string
+		values []string
+	}
+
+	entries := make([]mapEntry, 0, source.Len())
+	iter := source.MapRange()
+	for iter.Next() {
+		key, err := r.formatString(iter.Key(), options, "map key")
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.FormatOptions(iter.Value(), options)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		case nil:
+		default:
+			return nil, fmt.Errorf("cannot format map value of type '%s' as string", iter.Value().Type().String())
+		}
+		entries = append(entries, mapEntry{key: key, values: values})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	var ret []string
+	for _, entry := range entries {
+		for _, value := range entry.values {
+			ret = append(ret, entry.key+kvSeparator+value)
+		}
+	}
+	return strings.Join(ret, entrySeparator), nil
+}
+
+// formatString formats a value which must be formatted as a string, like list items and map keys.
+func (r Formatter) formatString(source reflect.Value, options resolver.Options, desc string) (string, error) {
+	value, err := r.FormatOptions(source, options)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("cannot format %s of type '%s' as string", desc, source.Type().String())
+	}
+	return s, nil
+}
+
+// optionValue returns an option value, or the default value if options is nil.
+func optionValue(options resolver.Options, name string, defaultValue string) string {
+	if options == nil {
+		return defaultValue
+	}
+	return options.Value(name, defaultValue)
+}
@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatValues(t *testing.T) {
+	formatter := NewFormatter()
+	b := true
+	var nilPtr *int
+	var nilSlice []string
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "format []string", input: []string{"test"}, want: []string{"test"}, wantErr: false},
+		{name: "format []int", input: []int{1, 2}, want: []string{"1", "2"}, wantErr: false},
+		{name: "format [2]int", input: [2]int{1, 2}, want: []string{"1", "2"}, wantErr: false},
+		{name: "format []*int", input: []*int{ptrTo(1), ptrTo(2)}, want: []string{"1", "2"}, wantErr: false},
+		{name: "format pointer", input: &b, want: "true", wantErr: false},
+		{name: "format nil pointer", input: nilPtr, want: nil, wantErr: false},
+		{name: "format nil slice", input: nilSlice, want: nil, wantErr: false},
+		{name: "failed unsupported slice type", input: []struct{}{{}}, wantErr: true},
+		{name: "format map", input: map[string]int{"b": 2, "a": 1}, want: "a=1,b=2", wantErr: false},
+		{name: "format map of slices", input: map[string][]string{"a": {"1", "2"}}, want: "a=1,a=2", wantErr: false},
+		{name: "format nil map", input: map[string]int(nil), want: nil, wantErr: false},
+		{name: "failed unsupported map key type", input: map[struct{}]int{{}: 1}, wantErr: true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatter.Format(reflect.ValueOf(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Format() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	formatter := NewDefaultValueFormatter()
+
+	type CustomType struct {
+		X int
+	}
+	type CustomStringType string
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "format string", input: "test", want: "test", wantErr: false},
+		{name: "format custom string", input: CustomStringType("test"), want: "test", wantErr: false},
+		{name: "format bool", input: true, want: "true", wantErr: false},
+		{name: "format int", input: int(-5), want: "-5", wantErr: false},
+		{name: "format int64", input: int64(5), want: "5", wantErr: false},
+		{name: "format int8", input: int8(5), want: "5", wantErr: false},
+		{name: "format uint", input: uint(5), want: "5", wantErr: false},
+		{name: "format uint64", input: uint64(5), want: "5", wantErr: false},
+		{name: "format float64", input: float64(5.5), want: "5.5", wantErr: false},
+		{name: "format float32", input: float32(5.5), want: "5.5", wantErr: false},
+		{name: "format custom type as itself", input: CustomType{5}, want: CustomType{5}, wantErr: false},
+		{name: "format time as itself", input: time.Time{}, want: time.Time{}, wantErr: false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatter.FormatValue(reflect.ValueOf(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_formatValue_customTypes(t *testing.T) {
+	formatter := NewDefaultValueFormatter(
+		WithCustomTypes(
+			NewValueFormatterTime(time.RFC3339),
+			NewValueFormatterTimeDuration(),
+		),
+		WithCustomTypesReflect(
+			NewValueFormatterReflectTextMarshaler(),
+		),
+	)
+
+	t1, _ := time.Parse(time.RFC3339, "2021-10-22T11:01:00Z")
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "format time", input: t1, want: "2021-10-22T11:01:00Z", wantErr: false},
+		{name: "format duration", input: 5 * time.Second, want: "5s", wantErr: false},
+		{name: "format text marshaler", input: net.IPv4(1, 2, 3, 4), want: "1.2.3.4", wantErr: false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatter.FormatValue(reflect.ValueOf(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
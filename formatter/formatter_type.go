@@ -0,0 +1,43 @@
+package formatter
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// ValueFormatterTime formats time.Time values.
+type ValueFormatterTime struct {
+	layout string
+}
+
+func NewValueFormatterTime(layout string) *ValueFormatterTime {
+	return &ValueFormatterTime{
+		layout: layout,
+	}
+}
+
+func (d *ValueFormatterTime) FormatTypeValue(source reflect.Value) (any, error) {
+	switch sv := source.Interface().(type) {
+	case time.Time:
+		return sv.Format(d.layout), nil
+	}
+	return nil, types.ErrCoerceUnknown
+}
+
+// ValueFormatterTimeDuration formats time.Duration values.
+type ValueFormatterTimeDuration struct {
+}
+
+func NewValueFormatterTimeDuration() *ValueFormatterTimeDuration {
+	return &ValueFormatterTimeDuration{}
+}
+
+func (d *ValueFormatterTimeDuration) FormatTypeValue(source reflect.Value) (any, error) {
+	switch sv := source.Interface().(type) {
+	case time.Duration:
+		return sv.String(), nil
+	}
+	return nil, types.ErrCoerceUnknown
+}
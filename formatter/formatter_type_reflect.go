@@ -0,0 +1,38 @@
+package formatter
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+)
+
+// ValueFormatterReflectTextMarshaler checks if source implements [encoding.TextMarshaler]
+// and use it to format to string.
+type ValueFormatterReflectTextMarshaler struct {
+}
+
+func NewValueFormatterReflectTextMarshaler() *ValueFormatterReflectTextMarshaler {
+	return &ValueFormatterReflectTextMarshaler{}
+}
+
+func (d *ValueFormatterReflectTextMarshaler) FormatTypeValueReflect(source reflect.Value) (any, error) {
+	var m encoding.TextMarshaler
+	if source.Type().Implements(textMarshalerType) {
+		m = source.Interface().(encoding.TextMarshaler)
+	} else if source.CanAddr() && reflect.PointerTo(source.Type()).Implements(textMarshalerType) {
+		m = source.Addr().Interface().(encoding.TextMarshaler)
+	} else {
+		return nil, types.ErrCoerceUnknown
+	}
+
+	b, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
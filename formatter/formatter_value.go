@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// ValueFormatter formats simple types for a Formatter.
+// It should NOT handle slices, pointers, or maps.
+type ValueFormatter interface {
+	// FormatValue formats the source value, usually to a string.
+	FormatValue(source reflect.Value) (any, error)
+}
+
+// TypeValueFormatter is a custom type handler for a ValueFormatter.
+// It should NOT process value using reflection (for performance reasons).
+// It must return [types.ErrCoerceUnknown] if the type is not supported.
+type TypeValueFormatter interface {
+	FormatTypeValue(source reflect.Value) (any, error)
+}
+
+// TypeValueFormatterReflect is a custom type handler for a ValueFormatter.
+// It SHOULD process value using reflection.
+// It must return [types.ErrCoerceUnknown] if the type is not supported.
+type TypeValueFormatterReflect interface {
+	FormatTypeValueReflect(source reflect.Value) (any, error)
+}
+
+type DefaultValueFormatter struct {
+	CustomTypes        []TypeValueFormatter
+	CustomTypesReflect []TypeValueFormatterReflect
+}
+
+func NewDefaultValueFormatter(options ...ValueOption) *DefaultValueFormatter {
+	ret := &DefaultValueFormatter{}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+type ValueOption func(formatter *DefaultValueFormatter)
+
+// WithCustomType adds a custom type.
+func WithCustomType(customType TypeValueFormatter) ValueOption {
+	return func(r *DefaultValueFormatter) {
+		r.CustomTypes = append(r.CustomTypes, customType)
+	}
+}
+
+// WithCustomTypeReflect adds a custom type that uses reflection.
+func WithCustomTypeReflect(customType TypeValueFormatterReflect) ValueOption {
+	return func(r *DefaultValueFormatter) {
+		r.CustomTypesReflect = append(r.CustomTypesReflect, customType)
+	}
+}
+
+// WithCustomTypes adds custom types.
+func WithCustomTypes(customTypes ...TypeValueFormatter) ValueOption {
+	return func(r *DefaultValueFormatter) {
+		r.CustomTypes = append(r.CustomTypes, customTypes...)
+	}
+}
+
+// WithCustomTypesReflect adds custom types that uses reflection.
+func WithCustomTypesReflect(customTypes ...TypeValueFormatterReflect) ValueOption {
+	return func(r *DefaultValueFormatter) {
+		r.CustomTypesReflect = append(r.CustomTypesReflect, customTypes...)
+	}
+}
+
+// FormatValue formats primitive values as strings. Values of unsupported types are returned unchanged, so
+// operations that don't need a string (like a JSON body) can use it directly.
+func (r DefaultValueFormatter) FormatValue(source reflect.Value) (any, error) {
+	// format custom types without reflection, like time.Time
+	if source.CanInterface() {
+		for _, customType := range r.CustomTypes {
+			value, err := customType.FormatTypeValue(source)
+			if err == nil {
+				return value, nil
+			}
+			if errors.Is(err, types.ErrCoerceUnknown) {
+				continue
+			}
+			return nil, err
+		}
+	}
+
+	// format primitive types without reflection
+	switch source.Type().Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(source.Bool()), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(source.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(source.Float(), 'f', -1, 64), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(source.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(source.Uint(), 10), nil
+	case reflect.String:
+		return source.String(), nil
+	}
+
+	// format custom types using reflection.
+	for _, customType := range r.CustomTypesReflect {
+		value, err := customType.FormatTypeValueReflect(source)
+		if err == nil {
+			return value, nil
+		}
+		if errors.Is(err, types.ErrCoerceUnknown) {
+			continue
+		}
+		return nil, err
+	}
+
+	if !source.CanInterface() {
+		return nil, types.ErrCoerceUnknown
+	}
+
+	return source.Interface(), nil
+}
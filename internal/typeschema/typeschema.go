@@ -0,0 +1,169 @@
+// Package typeschema contains the Go type walk shared by the schema exporters, like the openapi and jsonschema
+// packages.
+package typeschema
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rrgmc/instruct"
+)
+
+// Default tag option names.
+const (
+	DefaultDescriptionOption = "desc"
+	DefaultExampleOption     = "example"
+)
+
+// FieldOptions are the names of the tag options used to document fields.
+type FieldOptions struct {
+	DescriptionOption string
+	ExampleOption     string
+}
+
+// NewFieldOptions returns a FieldOptions with the default option names.
+func NewFieldOptions() FieldOptions {
+	return FieldOptions{
+		DescriptionOption: DefaultDescriptionOption,
+		ExampleOption:     DefaultExampleOption,
+	}
+}
+
+// Description returns the field description.
+func (o FieldOptions) Description(field *instruct.SchemaField) string {
+	return field.Options[o.DescriptionOption]
+}
+
+// Example returns the field example, and whether it was set.
+func (o FieldOptions) Example(field *instruct.SchemaField) (string, bool) {
+	value, ok := field.Options[o.ExampleOption]
+	return value, ok
+}
+
+// Builder builds the schemas of a specific format during a [Walk].
+type Builder[S any] interface {
+	// Custom returns the schema of types which describe themselves, or false to continue the walk.
+	Custom(typ reflect.Type) (S, bool)
+	// Special returns the schema of time.Time ("date-time"), time.Duration ("duration") and encoding.TextUnmarshaler
+	// ("text") types.
+	Special(name string) S
+	// Primitive returns the schema of a bool, integer, float or string kind.
+	Primitive(kind reflect.Kind) S
+	Array(items S) S
+	Map(values S) S
+	// Object returns the schema of a struct. properties is nil for recursive types.
+	Object(properties map[string]S) S
+	Unknown() S
+	// Type returns the JSON type of a schema, like "array" or "integer".
+	Type(schema S) string
+	// Items returns the items schema of an array schema.
+	Items(schema S) S
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Walk returns the schema of a Go type. Pointers are dereferenced, and structs are described using their JSON
+// field names, with embedded structs flattened.
+func Walk[S any](typ reflect.Type, builder Builder[S]) S {
+	return walk(typ, builder, map[reflect.Type]bool{})
+}
+
+func walk[S any](typ reflect.Type, builder Builder[S], visiting map[reflect.Type]bool) S {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if s, ok := builder.Custom(typ); ok {
+		return s
+	}
+	switch {
+	case typ == timeType:
+		return builder.Special("date-time")
+	case typ == durationType:
+		return builder.Special("duration")
+	case reflect.PointerTo(typ).Implements(textUnmarshalerType):
+		return builder.Special("text")
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return builder.Primitive(typ.Kind())
+	case reflect.Slice, reflect.Array:
+		return builder.Array(walk(typ.Elem(), builder, visiting))
+	case reflect.Map:
+		return builder.Map(walk(typ.Elem(), builder, visiting))
+	case reflect.Struct:
+		if visiting[typ] {
+			// recursive type
+			return builder.Object(nil)
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+		properties := map[string]S{}
+		structProperties(properties, typ, builder, visiting)
+		return builder.Object(properties)
+	}
+	return builder.Unknown()
+}
+
+// structProperties adds the struct fields as properties, using the JSON field names.
+func structProperties[S any](properties map[string]S, typ reflect.Type, builder Builder[S],
+	visiting map[reflect.Type]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				structProperties(properties, field.Type, builder, visiting)
+				continue
+			}
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			structProperties(properties, field.Type, builder, visiting)
+			continue
+		}
+		properties[name] = walk(field.Type, builder, visiting)
+	}
+}
+
+// Value converts a string value from a tag to the schema type. Arrays are split using ",".
+func Value[S any](schema S, value string, builder Builder[S]) any {
+	switch builder.Type(schema) {
+	case "array":
+		var ret []any
+		for _, item := range strings.Split(value, ",") {
+			ret = append(ret, Value(builder.Items(schema), item, builder))
+		}
+		return ret
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
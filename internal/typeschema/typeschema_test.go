@@ -0,0 +1,78 @@
+package typeschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testBuilder describes the schemas as strings, like "array(integer)".
+type testBuilder struct{}
+
+func (testBuilder) Custom(typ reflect.Type) (string, bool) { return "", false }
+func (testBuilder) Special(name string) string             { return name }
+func (testBuilder) Primitive(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "integer"
+}
+func (testBuilder) Array(items string) string { return "array(" + items + ")" }
+func (testBuilder) Map(values string) string  { return "map(" + values + ")" }
+func (testBuilder) Object(properties map[string]string) string {
+	if properties == nil {
+		return "object"
+	}
+	var props []string
+	for name, schema := range properties {
+		props = append(props, name+":"+schema)
+	}
+	sort.Strings(props)
+	return "object{" + strings.Join(props, ",") + "}"
+}
+func (testBuilder) Unknown() string { return "unknown" }
+func (testBuilder) Type(schema string) string {
+	typ, _, _ := strings.Cut(schema, "(")
+	return typ
+}
+func (testBuilder) Items(schema string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(schema, "array("), ")")
+}
+
+func TestWalk(t *testing.T) {
+	type Embedded struct {
+		E string
+	}
+	type Node struct {
+		Embedded
+		Name     string `json:"name"`
+		Skip     string `json:"-"`
+		Children []*Node
+		Created  time.Time
+		Timeout  time.Duration
+		Counts   map[string]int
+		internal string
+	}
+
+	require.Equal(t,
+		"object{Children:array(object),Counts:map(integer),Created:date-time,E:string,Timeout:duration,name:string}",
+		Walk[string](reflect.TypeOf(&Node{}), testBuilder{}))
+	require.Equal(t, "unknown", Walk[string](reflect.TypeOf(func() {}), testBuilder{}))
+}
+
+func TestValue(t *testing.T) {
+	require.Equal(t, int64(12), Value[string]("integer", "12", testBuilder{}))
+	require.Equal(t, 1.5, Value[string]("number", "1.5", testBuilder{}))
+	require.Equal(t, true, Value[string]("boolean", "true", testBuilder{}))
+	require.Equal(t, "x", Value[string]("integer", "x", testBuilder{}))
+	require.Equal(t, []any{int64(1), int64(2)}, Value[string]("array(integer)", "1,2", testBuilder{}))
+}
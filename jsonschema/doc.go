@@ -0,0 +1,2 @@
+// Package jsonschema exports a [github.com/rrgmc/instruct.Schema] as a JSON Schema (draft 2020-12).
+package jsonschema
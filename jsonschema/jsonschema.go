@@ -0,0 +1,261 @@
+package jsonschema
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/internal/typeschema"
+)
+
+// Draft is the JSON Schema version URI.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Default tag option names.
+const (
+	DefaultDescriptionOption = typeschema.DefaultDescriptionOption
+	DefaultExampleOption     = typeschema.DefaultExampleOption
+)
+
+// DurationPattern is the pattern of [time.Duration] strings, as parsed by [time.ParseDuration].
+const DurationPattern = `^[-+]?([0-9]*(\.[0-9]*)?[a-zµμ]+)+$`
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+// SchemaProvider can be implemented by custom types to supply their own schema fragment.
+type SchemaProvider interface {
+	JSONSchema() *Schema
+}
+
+// Exporter exports an [instruct.Schema] as a JSON Schema.
+type Exporter struct {
+	fieldOptions typeschema.FieldOptions
+}
+
+// NewExporter creates a new Exporter.
+func NewExporter(options ...Option) *Exporter {
+	ret := &Exporter{
+		fieldOptions: typeschema.NewFieldOptions(),
+	}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+type Option func(*Exporter)
+
+// WithDescriptionOption sets the tag option name used for descriptions. Default "desc".
+func WithDescriptionOption(name string) Option {
+	return func(e *Exporter) {
+		e.fieldOptions.DescriptionOption = name
+	}
+}
+
+// WithExampleOption sets the tag option name used for examples. Default "example".
+func WithExampleOption(name string) Option {
+	return func(e *Exporter) {
+		e.fieldOptions.ExampleOption = name
+	}
+}
+
+// Export exports the schema as a JSON Schema object with one property for each operation, like "query" or
+// "header", containing the fields read by it keyed by the tag name.
+// Nested "recurse" structs are nested objects, and "recurse_list" are arrays of objects, inside each operation.
+// Embedded structs are flattened. A struct option is exported as a property with the struct option tag name.
+func (e *Exporter) Export(schema *instruct.Schema) *Schema {
+	ret := newObject()
+	ret.Schema = Draft
+	ret.Title = schema.Type
+	if schema.StructOption != nil {
+		e.exportField(ret, schema.StructOption)
+	}
+	e.exportFields(ret, schema.Fields)
+	return ret
+}
+
+func (e *Exporter) exportFields(root *Schema, fields []*instruct.SchemaField) {
+	for _, field := range fields {
+		switch field.Operation {
+		case instruct.OperationIgnore:
+		case instruct.OperationRecurse, instruct.OperationRecurseList:
+			inner := newObject()
+			e.exportFields(inner, field.Struct.Fields)
+			for operation, innerGroup := range inner.Properties {
+				if field.Embedded && field.Operation == instruct.OperationRecurse {
+					// embedded structs don't add a level to the field path.
+					group := operationGroup(root, operation)
+					for name, property := range innerGroup.Properties {
+						group.Properties[name] = property
+					}
+					for _, name := range innerGroup.Required {
+						addRequired(group, name)
+					}
+					continue
+				}
+				var property *Schema
+				if field.Operation == instruct.OperationRecurseList {
+					property = &Schema{Type: "array", Items: innerGroup}
+				} else {
+					property = innerGroup
+				}
+				group := operationGroup(root, operation)
+				group.Properties[field.TagName] = property
+				if len(innerGroup.Required) > 0 && (field.Operation == instruct.OperationRecurse || field.Required) {
+					addRequired(group, field.TagName)
+				}
+			}
+			for _, name := range inner.Required {
+				addRequired(root, name)
+			}
+		default:
+			e.exportField(root, field)
+		}
+	}
+}
+
+func (e *Exporter) exportField(root *Schema, field *instruct.SchemaField) {
+	property := TypeSchema(field.GoType)
+	property.Description = e.fieldOptions.Description(field)
+	if field.HasDefault {
+		property.Default = typeschema.Value[*Schema](property, field.Default, schemaBuilder{})
+	}
+	if example, ok := e.fieldOptions.Example(field); ok {
+		property.Examples = []any{typeschema.Value[*Schema](property, example, schemaBuilder{})}
+	}
+
+	group := operationGroup(root, field.Operation)
+	group.Properties[field.TagName] = property
+	if field.Required {
+		addRequired(group, field.TagName)
+		addRequired(root, field.Operation)
+	}
+}
+
+func newObject() *Schema {
+	return &Schema{Type: "object", Properties: map[string]*Schema{}}
+}
+
+// operationGroup returns the object containing the fields of an operation, creating it if needed.
+func operationGroup(root *Schema, operation string) *Schema {
+	group, ok := root.Properties[operation]
+	if !ok {
+		group = newObject()
+		root.Properties[operation] = group
+	}
+	return group
+}
+
+func addRequired(schema *Schema, name string) {
+	for _, r := range schema.Required {
+		if r == name {
+			return
+		}
+	}
+	schema.Required = append(schema.Required, name)
+}
+
+var schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+
+// TypeSchema returns the JSON Schema of a Go type. Types implementing SchemaProvider return their own schema,
+// and structs are described using their JSON field names.
+func TypeSchema(typ reflect.Type) *Schema {
+	return typeschema.Walk[*Schema](typ, schemaBuilder{})
+}
+
+// schemaBuilder builds JSON Schemas for [typeschema.Walk].
+type schemaBuilder struct{}
+
+func (schemaBuilder) Custom(typ reflect.Type) (*Schema, bool) {
+	if !reflect.PointerTo(typ).Implements(schemaProviderType) {
+		return nil, false
+	}
+	s := reflect.New(typ).Interface().(SchemaProvider).JSONSchema()
+	if s == nil {
+		return nil, false
+	}
+	// return a copy so the caller can change it.
+	sc := *s
+	return &sc, true
+}
+
+func (schemaBuilder) Special(name string) *Schema {
+	switch name {
+	case "date-time":
+		return &Schema{Type: "string", Format: name}
+	case "duration":
+		return &Schema{Type: "string", Pattern: DurationPattern}
+	}
+	return &Schema{Type: "string"}
+}
+
+func (schemaBuilder) Primitive(kind reflect.Kind) *Schema {
+	switch kind {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8:
+		return integerSchema(math.MinInt8, math.MaxInt8)
+	case reflect.Int16:
+		return integerSchema(math.MinInt16, math.MaxInt16)
+	case reflect.Int32:
+		return integerSchema(math.MinInt32, math.MaxInt32)
+	case reflect.Uint8:
+		return integerSchema(0, math.MaxUint8)
+	case reflect.Uint16:
+		return integerSchema(0, math.MaxUint16)
+	case reflect.Uint32:
+		return integerSchema(0, math.MaxUint32)
+	case reflect.Uint, reflect.Uint64:
+		min := 0.0
+		return &Schema{Type: "integer", Minimum: &min}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return &Schema{Type: "string"}
+}
+
+func (schemaBuilder) Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func (schemaBuilder) Map(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+func (schemaBuilder) Object(properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties}
+}
+
+func (schemaBuilder) Unknown() *Schema {
+	return &Schema{}
+}
+
+func (schemaBuilder) Type(schema *Schema) string {
+	return schema.Type
+}
+
+func (schemaBuilder) Items(schema *Schema) *Schema {
+	return schema.Items
+}
+
+func integerSchema(min, max float64) *Schema {
+	return &Schema{Type: "integer", Minimum: &min, Maximum: &max}
+}
@@ -0,0 +1,147 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rrgmc/instruct"
+	"github.com/stretchr/testify/require"
+)
+
+type testColor string
+
+func (testColor) JSONSchema() *Schema {
+	return &Schema{Type: "string", Pattern: "^#[0-9a-f]{6}$"}
+}
+
+func TestExport(t *testing.T) {
+	type Body struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags,omitempty"`
+	}
+
+	type Common struct {
+		RequestID string `instruct:"header,name=X-Request-ID,required=false"`
+	}
+
+	type Paging struct {
+		Page  int `instruct:"query,required=false,default=1"`
+		Limit int `instruct:"query,desc=Page size,example=20"`
+	}
+
+	type Item struct {
+		SKU   string `instruct:"query"`
+		Count uint8  `instruct:"query,required=false"`
+	}
+
+	type Request struct {
+		Common
+		Token   string        `instruct:"header,name=X-Token,desc=Authentication token"`
+		Since   time.Time     `instruct:"query,required=false"`
+		Timeout time.Duration `instruct:"query,required=false"`
+		IDs     []int64       `instruct:"query,required=false"`
+		Color   testColor     `instruct:"query,required=false"`
+		Paging  Paging        `instruct:"recurse"`
+		Items   []Item        `instruct:"recurse_list,required=false"`
+		Body    Body          `instruct:"body"`
+		Ctx     string        `instruct:"-"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(Request{}))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(NewExporter().Export(schema))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "jsonschema.Request",
+		"type": "object",
+		"properties": {
+			"header": {
+				"type": "object",
+				"properties": {
+					"X-Request-ID": {"type": "string"},
+					"X-Token": {"type": "string", "description": "Authentication token"}
+				},
+				"required": ["X-Token"]
+			},
+			"query": {
+				"type": "object",
+				"properties": {
+					"since": {"type": "string", "format": "date-time"},
+					"timeout": {"type": "string", "pattern": "^[-+]?([0-9]*(\\.[0-9]*)?[a-zµμ]+)+$"},
+					"ids": {"type": "array", "items": {"type": "integer"}},
+					"color": {"type": "string", "pattern": "^#[0-9a-f]{6}$"},
+					"paging": {
+						"type": "object",
+						"properties": {
+							"page": {"type": "integer", "default": 1},
+							"limit": {"type": "integer", "description": "Page size", "examples": [20]}
+						},
+						"required": ["limit"]
+					},
+					"items": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"properties": {
+								"sku": {"type": "string"},
+								"count": {"type": "integer", "minimum": 0, "maximum": 255}
+							},
+							"required": ["sku"]
+						}
+					}
+				},
+				"required": ["paging"]
+			},
+			"body": {
+				"type": "object",
+				"properties": {
+					"body": {"type": "object", "properties": {
+						"name": {"type": "string"},
+						"tags": {"type": "array", "items": {"type": "string"}}
+					}}
+				},
+				"required": ["body"]
+			}
+		},
+		"required": ["header", "query", "body"]
+	}`, string(data))
+}
+
+func TestExportStructOption(t *testing.T) {
+	type Request struct {
+		_    instruct.StructOption `instruct:"body,desc=Request body"`
+		Name string                `json:"name"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(Request{}))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(NewExporter(WithDescriptionOption("desc")).Export(schema))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "jsonschema.Request",
+		"type": "object",
+		"properties": {
+			"body": {
+				"type": "object",
+				"properties": {
+					"_": {"type": "object", "description": "Request body", "properties": {"name": {"type": "string"}}}
+				},
+				"required": ["_"]
+			}
+		},
+		"required": ["body"]
+	}`, string(data))
+}
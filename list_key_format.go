@@ -0,0 +1,52 @@
+package instruct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ListKeyFormat formats and parses the names of the fields of list elements, like "items[0].name".
+// It is used by the "recurse_list" operation.
+type ListKeyFormat interface {
+	// Name returns the name of a field of a list element, like "items[0].name".
+	Name(listName string, index int, fieldName string) string
+	// Index parses the element index from a field name, like "items[0].name". Returns false if the name is not
+	// from an element of the list.
+	Index(listName string, name string) (int, bool)
+}
+
+// SeparatorListKeyFormat is a ListKeyFormat that surrounds the index with separators.
+type SeparatorListKeyFormat struct {
+	IndexPrefix    string // separator before the index, like "[".
+	IndexSuffix    string // separator after the index, like "]".
+	FieldSeparator string // separator between the index and the field name, like ".".
+}
+
+var (
+	ListKeyFormatBrackets    = SeparatorListKeyFormat{IndexPrefix: "[", IndexSuffix: "]", FieldSeparator: "."} // items[0].name
+	ListKeyFormatDots        = SeparatorListKeyFormat{IndexPrefix: ".", IndexSuffix: "", FieldSeparator: "."}  // items.0.name
+	ListKeyFormatUnderscores = SeparatorListKeyFormat{IndexPrefix: "_", IndexSuffix: "", FieldSeparator: "_"}  // items_0_name
+)
+
+func (f SeparatorListKeyFormat) Name(listName string, index int, fieldName string) string {
+	return listName + f.IndexPrefix + strconv.Itoa(index) + f.IndexSuffix + f.FieldSeparator + fieldName
+}
+
+func (f SeparatorListKeyFormat) Index(listName string, name string) (int, bool) {
+	s, ok := strings.CutPrefix(name, listName+f.IndexPrefix)
+	if !ok {
+		return 0, false
+	}
+	digits := 0
+	for digits < len(s) && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || !strings.HasPrefix(s[digits:], f.IndexSuffix+f.FieldSeparator) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(s[:digits])
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
@@ -61,6 +61,16 @@ func (l *mapTagsList) Set(t reflect.Type, m MapTags) {
 	l.list.Store(reflectElem(t), m)
 }
 
+// Types returns all the types that have MapTags.
+func (l *mapTagsList) Types() []reflect.Type {
+	var ret []reflect.Type
+	l.list.Range(func(key, _ any) bool {
+		ret = append(ret, key.(reflect.Type))
+		return true
+	})
+	return ret
+}
+
 // getMapTags checks if the type is a MapTags-compatible map and returns it.
 func getMapTags(v any) (MapTags, bool) {
 	if mv, ok := v.(MapTags); ok {
@@ -72,9 +82,13 @@ func getMapTags(v any) (MapTags, bool) {
 	return nil, false
 }
 
-// buildMapTagList builds a map with all MapTags keys to be used for comparison.
+// buildMapTagList builds a map with all MapTags keys to be used for comparison. The root MapTagsRulesKey is
+// checked separately.
 func buildMapTagList(m MapTags, lvl level, x map[string]bool) {
 	for mkey, mval := range m {
+		if mkey == MapTagsRulesKey && len(lvl.Path()) == 0 {
+			continue
+		}
 		x[lvl.StringPathWithName(mkey)] = true
 		if mv, ok := getMapTags(mval); ok {
 			buildMapTagList(mv, lvl.Append(mkey), x)
@@ -0,0 +1,190 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MapTagsBuilder builds MapTags for a struct type using field selectors, so typos and field renames are caught by
+// the compiler.
+type MapTagsBuilder[T any] struct {
+	root    reflect.Value // allocated *T, with nested struct pointers also allocated.
+	mapTags MapTags
+	err     error
+}
+
+// MapTagsFor returns a MapTagsBuilder for the struct type T.
+//
+//	mt, err := MapTagsFor[Request]().
+//		Field(func(t *Request) any { return &t.Inner.X }, "query,name=x").
+//		StructOption(func(t *Request) any { return t }, "body").
+//		Build()
+func MapTagsFor[T any]() *MapTagsBuilder[T] {
+	ret := &MapTagsBuilder[T]{
+		mapTags: MapTags{},
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		ret.err = fmt.Errorf("map tags builder type must be a struct, received: %s", typ.String())
+		return ret
+	}
+	ret.root = reflect.New(typ)
+	allocStructPointers(ret.root.Elem(), map[reflect.Type]bool{typ: true})
+	return ret
+}
+
+// Field sets the tag of the field returned by the selector, which must return a pointer to a field of t, like
+// "func(t *T) any { return &t.Inner.X }". Fields of inner structs, including pointers to structs, are set in
+// nested MapTags, so the inner struct uses "recurse". Embedded struct fields don't add a level, like in struct
+// tags. List elements can't be selected.
+func (b *MapTagsBuilder[T]) Field(selector func(t *T) any, tag string) *MapTagsBuilder[T] {
+	if b.err != nil {
+		return b
+	}
+	path, field, err := b.selectPath(selector)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if field == nil {
+		b.err = fmt.Errorf("map tags builder selector must return a field, not the struct itself")
+		return b
+	}
+	b.set(path, tag)
+	return b
+}
+
+// StructOption sets the struct option tag (the StructOptionMapTag key) of the struct returned by the selector.
+// Return t itself for the struct option of T, or a pointer to an inner struct field, like
+// "func(t *T) any { return &t.Inner }".
+func (b *MapTagsBuilder[T]) StructOption(selector func(t *T) any, tag string) *MapTagsBuilder[T] {
+	if b.err != nil {
+		return b
+	}
+	path, field, err := b.selectPath(selector)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if field != nil && !isStruct(field.Type) {
+		b.err = fmt.Errorf("map tags builder struct option field '%s' must be a struct but is '%s'",
+			strings.Join(path, "."), field.Type.String())
+		return b
+	}
+	b.set(append(path, StructOptionMapTag), tag)
+	return b
+}
+
+// Build returns the MapTags, or the first error found by the builder.
+func (b *MapTagsBuilder[T]) Build() (MapTags, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.mapTags, nil
+}
+
+// MustBuild is like Build but panics on error.
+func (b *MapTagsBuilder[T]) MustBuild() MapTags {
+	ret, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// set sets the tag on the path, creating the nested MapTags.
+func (b *MapTagsBuilder[T]) set(path []string, tag string) {
+	m := b.mapTags
+	for i, name := range path[:len(path)-1] {
+		switch v := m[name].(type) {
+		case nil:
+			inner := MapTags{}
+			m[name] = inner
+			m = inner
+		case MapTags:
+			m = v
+		default:
+			b.err = fmt.Errorf("map tags field '%s' conflicts with the tag of '%s'", strings.Join(path, "."),
+				strings.Join(path[:i+1], "."))
+			return
+		}
+	}
+	name := path[len(path)-1]
+	if _, exists := m[name]; exists {
+		b.err = fmt.Errorf("map tags field '%s' was already set", strings.Join(path, "."))
+		return
+	}
+	m[name] = tag
+}
+
+// selectPath calls the selector and returns the MapTags path of the returned pointer. The returned field is nil
+// if the selector returned the root struct.
+func (b *MapTagsBuilder[T]) selectPath(selector func(t *T) any) ([]string, *reflect.StructField, error) {
+	ptr := reflect.ValueOf(selector(b.root.Interface().(*T)))
+	if !ptr.IsValid() {
+		return nil, nil, fmt.Errorf("map tags builder selector must return a pointer to a field, received: nil")
+	}
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return nil, nil, fmt.Errorf("map tags builder selector must return a pointer to a field, received: %s",
+			ptr.Type().String())
+	}
+	if ptr.Pointer() == b.root.Pointer() && ptr.Type() == b.root.Type() {
+		return []string{}, nil, nil
+	}
+	path, field, ok := findFieldByAddr(b.root.Elem(), ptr.Pointer(), ptr.Type().Elem(), nil)
+	if !ok {
+		return nil, nil, fmt.Errorf("map tags builder selector returned a pointer to '%s' that is not a field of '%s'",
+			ptr.Type().Elem().String(), b.root.Type().Elem().String())
+	}
+	return path, field, nil
+}
+
+// findFieldByAddr finds the field of the struct with the address and type, recursing into inner structs and
+// allocated struct pointers.
+func findFieldByAddr(v reflect.Value, addr uintptr, typ reflect.Type, path []string) ([]string, *reflect.StructField, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fv := v.Field(i)
+
+		fieldPath := path
+		if !field.Anonymous {
+			fieldPath = append(append([]string{}, path...), field.Name)
+		}
+
+		if fv.UnsafeAddr() == addr && field.Type == typ {
+			return fieldPath, &field, true
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			if p, f, ok := findFieldByAddr(fv, addr, typ, fieldPath); ok {
+				return p, f, true
+			}
+		case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && !fv.IsNil():
+			if p, f, ok := findFieldByAddr(fv.Elem(), addr, typ, fieldPath); ok {
+				return p, f, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// allocStructPointers allocates the exported pointer to struct fields, so selectors can return their fields.
+// Recursive types are allocated only once.
+func allocStructPointers(v reflect.Value, visiting map[reflect.Type]bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fv := v.Field(i)
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			allocStructPointers(fv, visiting)
+		case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct &&
+			fv.CanSet() && !visiting[field.Type.Elem()]:
+			visiting[field.Type.Elem()] = true
+			fv.Set(reflect.New(field.Type.Elem()))
+			allocStructPointers(fv.Elem(), visiting)
+			delete(visiting, field.Type.Elem())
+		}
+	}
+}
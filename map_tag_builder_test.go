@@ -0,0 +1,147 @@
+package instruct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapTagsBuilder(t *testing.T) {
+	type Deep struct {
+		Z string
+	}
+	type Inner struct {
+		X    string
+		Deep *Deep
+	}
+	type Embedded struct {
+		E string
+	}
+	type Body struct {
+		Name string `json:"name"`
+	}
+	type DataType struct {
+		Embedded
+		A      string
+		Inner  Inner
+		PInner *Inner
+		Body   Body
+	}
+
+	m, err := MapTagsFor[DataType]().
+		Field(func(t *DataType) any { return &t.A }, "query,name=a").
+		Field(func(t *DataType) any { return &t.E }, "query").
+		Field(func(t *DataType) any { return &t.Inner.X }, "header,name=X-Value").
+		Field(func(t *DataType) any { return &t.Inner.Deep.Z }, "query,name=z").
+		Field(func(t *DataType) any { return &t.PInner.X }, "query,name=px").
+		Field(func(t *DataType) any { return &t.PInner.Deep }, "-").
+		StructOption(func(t *DataType) any { return &t.Body }, "body").
+		StructOption(func(t *DataType) any { return t }, "query,required=false,so_recurse=true").
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, MapTags{
+		"_": "query,required=false,so_recurse=true",
+		"A": "query,name=a",
+		"E": "query",
+		"Inner": MapTags{
+			"X": "header,name=X-Value",
+			"Deep": MapTags{
+				"Z": "query,name=z",
+			},
+		},
+		"PInner": MapTags{
+			"X":    "query,name=px",
+			"Deep": "-",
+		},
+		"Body": MapTags{
+			"_": "body",
+		},
+	}, m)
+
+	// decode using the built map tags, all must be used.
+	opt := GetTestDecoderOptions()
+	opt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), m)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	r := httptest.NewRequest(http.MethodPost, "/?a=1&e=2&z=3&px=4", strings.NewReader(`{"name":"n"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Value", "5")
+
+	var data DataType
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(&testDecodeContext{
+		allowReadBody: true,
+	})))
+	require.Equal(t, "1", data.A)
+	require.Equal(t, "2", data.E)
+	require.Equal(t, "5", data.Inner.X)
+	require.Equal(t, "3", data.Inner.Deep.Z)
+	require.Equal(t, "4", data.PInner.X)
+	require.Equal(t, "n", data.Body.Name)
+}
+
+func TestMapTagsBuilderErrors(t *testing.T) {
+	type Inner struct {
+		X string
+	}
+	type DataType struct {
+		A     string
+		Inner Inner
+	}
+
+	other := &Inner{}
+
+	tests := []struct {
+		name    string
+		builder *MapTagsBuilder[DataType]
+		err     string
+	}{
+		{
+			name:    "not a pointer",
+			builder: MapTagsFor[DataType]().Field(func(t *DataType) any { return t.A }, "query"),
+			err:     "map tags builder selector must return a pointer to a field, received: string",
+		},
+		{
+			name:    "not a field",
+			builder: MapTagsFor[DataType]().Field(func(t *DataType) any { return &other.X }, "query"),
+			err:     "map tags builder selector returned a pointer to 'string' that is not a field of 'instruct.DataType'",
+		},
+		{
+			name:    "root as field",
+			builder: MapTagsFor[DataType]().Field(func(t *DataType) any { return t }, "query"),
+			err:     "map tags builder selector must return a field, not the struct itself",
+		},
+		{
+			name: "already set",
+			builder: MapTagsFor[DataType]().
+				Field(func(t *DataType) any { return &t.A }, "query").
+				Field(func(t *DataType) any { return &t.A }, "header"),
+			err: "map tags field 'A' was already set",
+		},
+		{
+			name: "conflict",
+			builder: MapTagsFor[DataType]().
+				Field(func(t *DataType) any { return &t.Inner }, "recurse").
+				Field(func(t *DataType) any { return &t.Inner.X }, "header"),
+			err: "map tags field 'Inner.X' conflicts with the tag of 'Inner'",
+		},
+		{
+			name:    "struct option on non-struct",
+			builder: MapTagsFor[DataType]().StructOption(func(t *DataType) any { return &t.A }, "body"),
+			err:     "map tags builder struct option field 'A' must be a struct but is 'string'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			require.EqualError(t, err, tt.err)
+		})
+	}
+
+	_, err := MapTagsFor[int]().Build()
+	require.EqualError(t, err, "map tags builder type must be a struct, received: int")
+}
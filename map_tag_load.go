@@ -0,0 +1,174 @@
+package instruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MapTagsFromJSON reads MapTags from a JSON object. Values must be strings with the tag text, or objects with the
+// fields of inner structs. Keys can also be dotted paths, like "Inner.Field", which are merged with the nested
+// objects. Errors contain the line and the key path of the invalid value.
+func MapTagsFromJSON(r io.Reader) (MapTags, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &mapTagsLoader{
+		data: data,
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	ret := MapTags{}
+	if err := l.loadRoot(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MapTagsFromFile reads MapTags from a JSON file. See [MapTagsFromJSON] for the format.
+func MapTagsFromFile(filename string) (MapTags, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ret, err := MapTagsFromJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return ret, nil
+}
+
+type mapTagsLoader struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+func (l *mapTagsLoader) loadRoot(m MapTags) error {
+	tok, err := l.dec.Token()
+	if err != nil {
+		return l.syntaxError(err)
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("line %d: map tags must be a JSON object", l.line())
+	}
+	if err := l.loadObject(m, level{}); err != nil {
+		return err
+	}
+	if _, err := l.dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("line %d: unexpected data after map tags object", l.line())
+	}
+	return nil
+}
+
+// loadObject loads the object fields into m, after its opening brace was read. lvl is the path of m.
+func (l *mapTagsLoader) loadObject(m MapTags, lvl level) error {
+	for l.dec.More() {
+		tok, err := l.dec.Token()
+		if err != nil {
+			return l.syntaxError(err)
+		}
+		key := tok.(string)
+		line := l.line()
+
+		path := strings.Split(key, ".")
+		for _, name := range path {
+			if name == "" {
+				return fmt.Errorf("line %d: invalid map tags key '%s'", line, lvl.StringPathWithName(key))
+			}
+		}
+		keyLevel := lvl
+		for _, name := range path {
+			keyLevel = keyLevel.Append(name)
+		}
+
+		// find or create the parent of dotted keys.
+		parent := m
+		for i, name := range path[:len(path)-1] {
+			switch v := parent[name].(type) {
+			case nil:
+				inner := MapTags{}
+				parent[name] = inner
+				parent = inner
+			case MapTags:
+				parent = v
+			default:
+				return fmt.Errorf("line %d: map tags key '%s' conflicts with the tag of '%s'", line,
+					keyLevel.StringPath(), lvl.StringPathWithName(strings.Join(path[:i+1], ".")))
+			}
+		}
+		name := path[len(path)-1]
+
+		tok, err = l.dec.Token()
+		if err != nil {
+			return l.syntaxError(err)
+		}
+		switch v := tok.(type) {
+		case string:
+			if _, exists := parent[name]; exists {
+				return fmt.Errorf("line %d: duplicated map tags key '%s'", line, keyLevel.StringPath())
+			}
+			parent[name] = v
+		case json.Delim:
+			if v != '{' {
+				return fmt.Errorf("line %d: invalid map tags value type for key '%s': array (only 'string' and 'object' are allowed)",
+					line, keyLevel.StringPath())
+			}
+			inner, ok := parent[name].(MapTags)
+			if !ok {
+				if _, exists := parent[name]; exists {
+					return fmt.Errorf("line %d: duplicated map tags key '%s'", line, keyLevel.StringPath())
+				}
+				inner = MapTags{}
+				parent[name] = inner
+			}
+			if err := l.loadObject(inner, keyLevel); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("line %d: invalid map tags value type for key '%s': %s (only 'string' and 'object' are allowed)",
+				line, keyLevel.StringPath(), jsonTokenType(tok))
+		}
+	}
+
+	// closing brace
+	if _, err := l.dec.Token(); err != nil {
+		return l.syntaxError(err)
+	}
+	return nil
+}
+
+// line returns the line of the current decoder offset.
+func (l *mapTagsLoader) line() int {
+	return bytes.Count(l.data[:l.dec.InputOffset()], []byte("\n")) + 1
+}
+
+func (l *mapTagsLoader) syntaxError(err error) error {
+	var serr *json.SyntaxError
+	if errors.As(err, &serr) {
+		return fmt.Errorf("line %d: %w", bytes.Count(l.data[:serr.Offset], []byte("\n"))+1, err)
+	}
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("line %d: unexpected end of JSON input", l.line())
+	}
+	return fmt.Errorf("line %d: %w", l.line(), err)
+}
+
+func jsonTokenType(tok json.Token) string {
+	switch tok.(type) {
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", tok)
+	}
+}
@@ -0,0 +1,141 @@
+package instruct
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MapTagsConflict is a MapTags key defined by more than one layer in [MergeMapTags]. Keys where all layers
+// define inner fields (nested MapTags) are merged and are not conflicts.
+type MapTagsConflict struct {
+	Path   string // dotted key path, like "Inner.X".
+	Layers []int  // indexes of the layers defining the key, in order. The last one is used.
+}
+
+func (c MapTagsConflict) String() string {
+	layers := make([]string, len(c.Layers))
+	for i, layer := range c.Layers {
+		layers[i] = fmt.Sprint(layer)
+	}
+	return fmt.Sprintf("map tags key '%s' is defined by layers %s", c.Path, strings.Join(layers, ", "))
+}
+
+// MapTagsConflictError is returned by [MergeMapTagsWithOptions] when MergeMapTagsOptions.ErrorOnConflict is
+// set and there are conflicts.
+type MapTagsConflictError struct {
+	Conflicts []MapTagsConflict
+}
+
+func (e *MapTagsConflictError) Error() string {
+	items := make([]string, len(e.Conflicts))
+	for i, conflict := range e.Conflicts {
+		items[i] = conflict.String()
+	}
+	return fmt.Sprintf("map tags conflicts: %s", strings.Join(items, "; "))
+}
+
+// MergeMapTagsOptions are the options for [MergeMapTagsWithOptions].
+type MergeMapTagsOptions struct {
+	ErrorOnConflict bool // return a *MapTagsConflictError if there are conflicts.
+}
+
+// MergeMapTags deep merges MapTags layers into a new MapTags, with later layers having precedence. Nested MapTags
+// are merged, and any other key defined by more than one layer is replaced by the last one and reported as a
+// conflict, sorted by path. MapTagsRules are concatenated with the rules of later layers first, so they are
+// checked before the earlier ones. nil layers are skipped, and the layers are not changed.
+//
+// It can be used to combine MapTags from multiple sources before setting them with DefaultMapTagsSet, as each
+// call replaces the MapTags of the type.
+func MergeMapTags(layers ...MapTags) (MapTags, []MapTagsConflict) {
+	ret, conflicts, _ := MergeMapTagsWithOptions(MergeMapTagsOptions{}, layers...)
+	return ret, conflicts
+}
+
+// MergeMapTagsWithOptions is like [MergeMapTags] but with options.
+func MergeMapTagsWithOptions(options MergeMapTagsOptions, layers ...MapTags) (MapTags, []MapTagsConflict, error) {
+	ret := MapTags{}
+	defs := map[string][]mapTagsLayerDef{}
+	for i, layer := range layers {
+		if layer == nil {
+			continue
+		}
+		mergeMapTagsLayer(ret, layer, i, level{}, defs)
+	}
+
+	var conflicts []MapTagsConflict
+	for path, pathDefs := range defs {
+		if len(pathDefs) < 2 {
+			continue
+		}
+		conflict := MapTagsConflict{Path: path}
+		isConflict := false
+		for _, def := range pathDefs {
+			conflict.Layers = append(conflict.Layers, def.layer)
+			if !def.isMap {
+				isConflict = true
+			}
+		}
+		if isConflict {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+
+	if options.ErrorOnConflict && len(conflicts) > 0 {
+		return nil, conflicts, &MapTagsConflictError{Conflicts: conflicts}
+	}
+	return ret, conflicts, nil
+}
+
+// mapTagsLayerDef is a definition of a key by a layer.
+type mapTagsLayerDef struct {
+	layer int
+	isMap bool
+}
+
+// mergeMapTagsLayer merges one layer into dst, recording the layers defining each key in defs.
+func mergeMapTagsLayer(dst MapTags, src MapTags, layer int, lvl level, defs map[string][]mapTagsLayerDef) {
+	for key, value := range src {
+		if key == MapTagsRulesKey && len(lvl.Path()) == 0 {
+			if rules, ok := mergeMapTagsRules(value, dst[key]); ok {
+				dst[key] = rules
+				continue
+			}
+		}
+
+		path := lvl.StringPathWithName(key)
+		srcMap, srcIsMap := getMapTags(value)
+		defs[path] = append(defs[path], mapTagsLayerDef{layer: layer, isMap: srcIsMap})
+
+		if !srcIsMap {
+			dst[key] = value
+			continue
+		}
+		dstMap, dstIsMap := dst[key].(MapTags)
+		if !dstIsMap {
+			// not set or replacing a non-map value.
+			dstMap = MapTags{}
+			dst[key] = dstMap
+		}
+		mergeMapTagsLayer(dstMap, srcMap, layer, lvl.Append(key), defs)
+	}
+}
+
+// mergeMapTagsRules returns the layer rules followed by the current ones, or false if any of them is not a
+// MapTagsRules.
+func mergeMapTagsRules(layerRules any, current any) (MapTagsRules, bool) {
+	lr, err := (MapTags{MapTagsRulesKey: layerRules}).rules()
+	if err != nil {
+		return nil, false
+	}
+	var cr MapTagsRules
+	if current != nil {
+		if cr, err = (MapTags{MapTagsRulesKey: current}).rules(); err != nil {
+			return nil, false
+		}
+	}
+	return append(append(MapTagsRules{}, lr...), cr...), true
+}
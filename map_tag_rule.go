@@ -0,0 +1,153 @@
+package instruct
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MapTagsRulesKey is the root MapTags key that holds the MapTagsRules.
+const MapTagsRulesKey = "*"
+
+const mapTagRuleValueKey string = "map-tag-rule"
+
+// MapTagsRule sets the tag of all fields matching both Path and Type. Fields with an exact MapTags key don't use
+// rules.
+//
+//	MapTags{
+//		"Filters": "recurse",
+//		MapTagsRulesKey: MapTagsRules{
+//			{Path: "Filters.*", Type: reflect.TypeOf(""), Tag: "query"},
+//			{Type: reflect.TypeOf(time.Time{}), Tag: "query,layout=2006-01-02"},
+//			{Path: "Meta.*", Tag: "header"},
+//		},
+//	}
+type MapTagsRule struct {
+	// Path is a glob of the field path, using "." as the separator. Each item is matched using [path.Match],
+	// and a "**" item matches any number of items. Blank matches all paths.
+	Path string
+	// Type is the field type. Pointers to the type also match. nil matches all types.
+	Type reflect.Type
+	// Tag is the tag set on the matching fields.
+	Tag string
+}
+
+// String returns a description of the rule.
+func (r MapTagsRule) String() string {
+	var b strings.Builder
+	if r.Path != "" {
+		fmt.Fprintf(&b, "path=%s ", r.Path)
+	}
+	if r.Type != nil {
+		fmt.Fprintf(&b, "type=%s ", r.Type.String())
+	}
+	fmt.Fprintf(&b, "tag=%s", r.Tag)
+	return b.String()
+}
+
+// match returns whether the rule matches the field path and type.
+func (r MapTagsRule) match(fieldPath []string, typ reflect.Type) (bool, error) {
+	if r.Type != nil && typ != r.Type && !(typ.Kind() == reflect.Pointer && typ.Elem() == r.Type) {
+		return false, nil
+	}
+	if r.Path == "" {
+		return true, nil
+	}
+	return matchPathGlob(strings.Split(r.Path, "."), fieldPath)
+}
+
+// MapTagsRules is an ordered list of MapTagsRule, set in the MapTagsRulesKey of the root MapTags. The first
+// matching rule is used.
+type MapTagsRules []MapTagsRule
+
+// rules returns the MapTagsRules of the MapTags, if any.
+func (m MapTags) rules() (MapTagsRules, error) {
+	v, ok := m[MapTagsRulesKey]
+	if !ok {
+		return nil, nil
+	}
+	switch rv := v.(type) {
+	case MapTagsRules:
+		return rv, nil
+	case []MapTagsRule:
+		return rv, nil
+	}
+	return nil, fmt.Errorf("map tags key '%s' must be a MapTagsRules but is '%T'", MapTagsRulesKey, v)
+}
+
+// findRule returns the first rule matching the field path and type, and its index.
+func (m MapTags) findRule(fieldPath []string, typ reflect.Type) (MapTagsRule, int, bool, error) {
+	rules, err := m.rules()
+	if err != nil {
+		return MapTagsRule{}, 0, false, err
+	}
+	for i, rule := range rules {
+		ok, err := rule.match(fieldPath, typ)
+		if err != nil {
+			return MapTagsRule{}, 0, false, fmt.Errorf("map tags rule %d (%s): %w", i, rule, err)
+		}
+		if ok {
+			return rule, i, true, nil
+		}
+	}
+	return MapTagsRule{}, 0, false, nil
+}
+
+// checkRules returns an error if the rules key is not a MapTagsRules or any rule path is malformed, so these are
+// reported once and not for each field.
+func (m MapTags) checkRules() error {
+	rules, err := m.rules()
+	if err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		if rule.Path == "" {
+			continue
+		}
+		for _, item := range strings.Split(rule.Path, ".") {
+			if _, err := path.Match(item, ""); err != nil {
+				return fmt.Errorf("map tags rule %d (%s): %w", i, rule, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUnusedRules returns an error if any rule didn't match any field.
+func (m MapTags) checkUnusedRules(usedRules map[string]bool) error {
+	rules, err := m.rules()
+	if err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		if !usedRules[strconv.Itoa(i)] {
+			return fmt.Errorf("map tags rule %d (%s) was declared but not used", i, rule)
+		}
+	}
+	return nil
+}
+
+// matchPathGlob matches the path items with the glob items.
+func matchPathGlob(pattern []string, fieldPath []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(fieldPath) == 0, nil
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(fieldPath); i++ {
+			if ok, err := matchPathGlob(pattern[1:], fieldPath[i:]); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	if len(fieldPath) == 0 {
+		return false, nil
+	}
+	ok, err := path.Match(pattern[0], fieldPath[0])
+	if err != nil || !ok {
+		return false, err
+	}
+	return matchPathGlob(pattern[1:], fieldPath[1:])
+}
@@ -0,0 +1,155 @@
+package instruct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapTagsRules(t *testing.T) {
+	type Filters struct {
+		Name   string
+		Status string
+		Limit  int
+	}
+	type Meta struct {
+		RequestID string
+		Trace     *string
+	}
+	type DataType struct {
+		ID      string `instruct:"header,name=X-ID"`
+		Filters Filters
+		Meta    Meta `instruct:"recurse"`
+	}
+
+	mapTags := MapTags{
+		"Filters": MapTags{
+			"Limit": "query,name=max",
+		},
+		MapTagsRulesKey: MapTagsRules{
+			{Path: "Filters.*", Type: reflect.TypeOf(""), Tag: "query"},
+			{Path: "Meta.*", Tag: "header,required=false"},
+		},
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), mapTags)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	r := httptest.NewRequest(http.MethodGet, "/?name=n&status=s&max=10", nil)
+	r.Header.Set("X-ID", "1")
+	r.Header.Set("RequestID", "r")
+	r.Header.Set("Trace", "t")
+
+	var data DataType
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+	require.Equal(t, "1", data.ID)
+	require.Equal(t, "n", data.Filters.Name)
+	require.Equal(t, "s", data.Filters.Status)
+	require.Equal(t, 10, data.Filters.Limit)
+	require.Equal(t, "r", data.Meta.RequestID)
+	require.NotNil(t, data.Meta.Trace)
+	require.Equal(t, "t", *data.Meta.Trace)
+
+	schema, err := dec.Schema(reflect.TypeOf(DataType{}))
+	require.NoError(t, err)
+	require.Empty(t, schema.Fields[1].Struct.Fields[2].MapTagsRule)
+	require.Equal(t, "0 (path=Filters.* type=string tag=query)", schema.Fields[1].Struct.Fields[0].MapTagsRule)
+	require.Contains(t, schema.String(),
+		"- Trace (*string): header name=trace required=false [map tags rule 1 (path=Meta.* tag=header,required=false)]")
+}
+
+func TestMapTagsRulesErrors(t *testing.T) {
+	type Inner struct {
+		X int
+	}
+	type DataType struct {
+		A     string
+		B     int `instruct:"query"`
+		Inner Inner
+	}
+
+	tests := []struct {
+		name    string
+		mapTags MapTags
+		err     string
+	}{
+		{
+			name: "exact key has priority",
+			mapTags: MapTags{
+				"A": "query",
+				"Inner": MapTags{
+					"X": "query",
+				},
+				MapTagsRulesKey: MapTagsRules{
+					{Path: "A", Tag: "header"},
+				},
+			},
+			err: "map tags rule 0 (path=A tag=header) was declared but not used",
+		},
+		{
+			name: "type mismatch",
+			mapTags: MapTags{
+				"A": "query",
+				MapTagsRulesKey: MapTagsRules{
+					{Path: "Inner.**", Tag: "query"},
+					{Type: reflect.TypeOf(""), Tag: "header"},
+				},
+			},
+			err: "map tags rule 1 (type=string tag=header) was declared but not used",
+		},
+		{
+			name: "bad pattern",
+			mapTags: MapTags{
+				MapTagsRulesKey: MapTagsRules{
+					{Path: "[", Tag: "query"},
+				},
+			},
+			err: "map tags rule 0 (path=[ tag=query): syntax error in pattern",
+		},
+		{
+			name: "invalid rules type",
+			mapTags: MapTags{
+				"A":             "query",
+				MapTagsRulesKey: "query",
+			},
+			err: "map tags key '*' must be a MapTagsRules but is 'string'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opt := GetTestDecoderOptions()
+			_, err := buildStructInfo(reflect.TypeOf(DataType{}), test.mapTags, opt.structInfoOptions())
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"A", "A", true},
+		{"A", "B", false},
+		{"Meta.*", "Meta.X", true},
+		{"Meta.*", "Meta.X.Y", false},
+		{"Meta.*", "Meta", false},
+		{"Meta.**", "Meta.X.Y", true},
+		{"**.ID", "ID", true},
+		{"**.ID", "A.B.ID", true},
+		{"**.ID", "A.B.IDs", false},
+		{"*.Created*", "Item.CreatedAt", true},
+	}
+	for _, test := range tests {
+		ok, err := matchPathGlob(strings.Split(test.pattern, "."), strings.Split(test.path, "."))
+		require.NoError(t, err)
+		require.Equal(t, test.match, ok, "pattern '%s' path '%s'", test.pattern, test.path)
+	}
+}
@@ -1,6 +1,12 @@
 package instruct
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -168,3 +174,176 @@ func TestMapTagsUnusedFields(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestMapTagsFromJSON(t *testing.T) {
+	m, err := MapTagsFromJSON(strings.NewReader(`{
+		"_": "body,so_recurse=true",
+		"A": "query,name=a",
+		"Inner": {
+			"X": "header"
+		},
+		"Inner.Y": "query",
+		"Other.Deep.Z": "form"
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, MapTags{
+		"_": "body,so_recurse=true",
+		"A": "query,name=a",
+		"Inner": MapTags{
+			"X": "header",
+			"Y": "query",
+		},
+		"Other": MapTags{
+			"Deep": MapTags{
+				"Z": "form",
+			},
+		},
+	}, m)
+}
+
+func TestMapTagsFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "number",
+			data: "{\n\"A\": \"query\",\n\"Inner\": {\n\"X\": 12\n}\n}",
+			err:  "line 4: invalid map tags value type for key 'Inner.X': number (only 'string' and 'object' are allowed)",
+		},
+		{
+			name: "array",
+			data: "{\"A\": [\"query\"]}",
+			err:  "line 1: invalid map tags value type for key 'A': array (only 'string' and 'object' are allowed)",
+		},
+		{
+			name: "conflict",
+			data: "{\n\"A\": \"query\",\n\"A.B\": \"query\"\n}",
+			err:  "line 3: map tags key 'A.B' conflicts with the tag of 'A'",
+		},
+		{
+			name: "duplicated",
+			data: "{\"A\": {\"B\": \"query\"}, \"A.B\": \"header\"}",
+			err:  "line 1: duplicated map tags key 'A.B'",
+		},
+		{
+			name: "empty key segment",
+			data: "{\"A..B\": \"query\"}",
+			err:  "line 1: invalid map tags key 'A..B'",
+		},
+		{
+			name: "not an object",
+			data: "[]",
+			err:  "line 1: map tags must be a JSON object",
+		},
+		{
+			name: "syntax error",
+			data: "{\n\"A\": \"query\",\n\"B\" \"query\"\n}",
+			err:  "line 3: invalid character '\"' after object key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MapTagsFromJSON(strings.NewReader(tt.data))
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestMapTagsFromFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "maptags.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"A": "query", "Inner.X": true}`), 0o600))
+
+	_, err := MapTagsFromFile(filename)
+	require.EqualError(t, err, filename+": line 1: invalid map tags value type for key 'Inner.X': boolean (only 'string' and 'object' are allowed)")
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"A": "query", "Inner.X": "header"}`), 0o600))
+
+	m, err := MapTagsFromFile(filename)
+	require.NoError(t, err)
+
+	type Inner struct {
+		X string
+	}
+	type DataType struct {
+		A     string
+		Inner Inner
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), m)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	r := httptest.NewRequest(http.MethodGet, "/?a=1", nil)
+	r.Header.Set("X", "2")
+
+	var data DataType
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+	require.Equal(t, DataType{A: "1", Inner: Inner{X: "2"}}, data)
+}
+
+func TestMergeMapTags(t *testing.T) {
+	base := MapTags{
+		"A": "query",
+		"Inner": MapTags{
+			"X": "header",
+			"Y": "header",
+		},
+		"Body":          "body",
+		MapTagsRulesKey: MapTagsRules{{Path: "Meta.*", Tag: "header"}},
+	}
+	team := MapTags{
+		"B": "query",
+		"Inner": map[string]any{
+			"Y": "query",
+			"Z": "query",
+		},
+		"Body": MapTags{
+			"_": "body",
+		},
+		MapTagsRulesKey: MapTagsRules{{Path: "Meta.ID", Tag: "path"}},
+	}
+	call := MapTags{
+		"A": "header,name=a",
+	}
+
+	m, conflicts := MergeMapTags(base, nil, team, call)
+	require.Equal(t, MapTags{
+		"A": "header,name=a",
+		"B": "query",
+		"Inner": MapTags{
+			"X": "header",
+			"Y": "query",
+			"Z": "query",
+		},
+		"Body": MapTags{
+			"_": "body",
+		},
+		MapTagsRulesKey: MapTagsRules{
+			{Path: "Meta.ID", Tag: "path"},
+			{Path: "Meta.*", Tag: "header"},
+		},
+	}, m)
+	require.Equal(t, []MapTagsConflict{
+		{Path: "A", Layers: []int{0, 3}},
+		{Path: "Body", Layers: []int{0, 2}},
+		{Path: "Inner.Y", Layers: []int{0, 2}},
+	}, conflicts)
+
+	// layers must not be changed.
+	require.Equal(t, MapTags{"X": "header", "Y": "header"}, base["Inner"])
+	require.Len(t, base[MapTagsRulesKey], 1)
+
+	_, conflicts, err := MergeMapTagsWithOptions(MergeMapTagsOptions{ErrorOnConflict: true}, base, team)
+	require.Len(t, conflicts, 2)
+	var conflictErr *MapTagsConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	require.EqualError(t, err, "map tags conflicts: map tags key 'Body' is defined by layers 0, 1; "+
+		"map tags key 'Inner.Y' is defined by layers 0, 1")
+
+	m, _, err = MergeMapTagsWithOptions(MergeMapTagsOptions{ErrorOnConflict: true}, base, MapTags{"C": "query"})
+	require.NoError(t, err)
+	require.Equal(t, "query", m["A"])
+}
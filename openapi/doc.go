@@ -0,0 +1,3 @@
+// Package openapi generates OpenAPI 3 "parameters" and "requestBody" fragments from a
+// [github.com/rrgmc/instruct.Schema].
+package openapi
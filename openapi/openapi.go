@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rrgmc/instruct"
+	"github.com/rrgmc/instruct/internal/typeschema"
+)
+
+// Location is where a value is located in an OpenAPI operation.
+type Location string
+
+const (
+	LocationQuery  Location = "query"
+	LocationHeader Location = "header"
+	LocationPath   Location = "path"
+	LocationCookie Location = "cookie"
+	LocationBody   Location = "body" // the request body.
+)
+
+// Default tag option names.
+const (
+	DefaultDescriptionOption = typeschema.DefaultDescriptionOption
+	DefaultExampleOption     = typeschema.DefaultExampleOption
+	DefaultBodyContentType   = "application/json"
+)
+
+// OperationMapper maps an instruct operation to an OpenAPI location. Return false to skip fields using the
+// operation.
+type OperationMapper func(operation string) (Location, bool)
+
+// MapOperations returns an OperationMapper that maps the operations using a map.
+func MapOperations(m map[string]Location) OperationMapper {
+	return func(operation string) (Location, bool) {
+		location, ok := m[operation]
+		return location, ok
+	}
+}
+
+// DefaultOperationMapper maps the "query", "header", "path" and "cookie" operations to the location with the
+// same name, and the "body" operation to the request body.
+var DefaultOperationMapper = MapOperations(map[string]Location{
+	"query":  LocationQuery,
+	"header": LocationHeader,
+	"path":   LocationPath,
+	"cookie": LocationCookie,
+	"body":   LocationBody,
+})
+
+// Operation contains the OpenAPI operation fragments generated from a struct.
+type Operation struct {
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+}
+
+// Parameter is an OpenAPI parameter object.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+	Example     any     `json:"example,omitempty"`
+}
+
+// RequestBody is an OpenAPI request body object.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// MediaType is an OpenAPI media type object.
+type MediaType struct {
+	Schema  *Schema `json:"schema,omitempty"`
+	Example any     `json:"example,omitempty"`
+}
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Default              any                `json:"default,omitempty"`
+}
+
+// Generator generates OpenAPI fragments from an [instruct.Schema].
+type Generator struct {
+	operationMapper OperationMapper
+	fieldOptions    typeschema.FieldOptions
+	bodyContentType string
+}
+
+// NewGenerator creates a new Generator.
+func NewGenerator(options ...Option) *Generator {
+	ret := &Generator{
+		operationMapper: DefaultOperationMapper,
+		fieldOptions:    typeschema.NewFieldOptions(),
+		bodyContentType: DefaultBodyContentType,
+	}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+type Option func(*Generator)
+
+// WithOperationMapper sets the mapping from instruct operations to OpenAPI locations.
+func WithOperationMapper(operationMapper OperationMapper) Option {
+	return func(g *Generator) {
+		g.operationMapper = operationMapper
+	}
+}
+
+// WithDescriptionOption sets the tag option name used for descriptions. Default "desc".
+func WithDescriptionOption(name string) Option {
+	return func(g *Generator) {
+		g.fieldOptions.DescriptionOption = name
+	}
+}
+
+// WithExampleOption sets the tag option name used for examples. Default "example".
+func WithExampleOption(name string) Option {
+	return func(g *Generator) {
+		g.fieldOptions.ExampleOption = name
+	}
+}
+
+// WithBodyContentType sets the content type of the request body. Default "application/json".
+func WithBodyContentType(contentType string) Option {
+	return func(g *Generator) {
+		g.bodyContentType = contentType
+	}
+}
+
+// Generate generates the OpenAPI parameters and request body of the schema.
+// Fields using "recurse_list" can't be described as OpenAPI parameters and are skipped.
+func (g *Generator) Generate(schema *instruct.Schema) (*Operation, error) {
+	ret := &Operation{}
+	if schema.StructOption != nil {
+		if err := g.generateField(ret, schema.StructOption); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.generateFields(ret, schema.Fields); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (g *Generator) generateFields(op *Operation, fields []*instruct.SchemaField) error {
+	for _, field := range fields {
+		switch field.Operation {
+		case instruct.OperationIgnore, instruct.OperationRecurseList:
+		case instruct.OperationRecurse:
+			if err := g.generateFields(op, field.Struct.Fields); err != nil {
+				return err
+			}
+		default:
+			if err := g.generateField(op, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Generator) generateField(op *Operation, field *instruct.SchemaField) error {
+	location, ok := g.operationMapper(field.Operation)
+	if !ok {
+		return nil
+	}
+
+	schema := TypeSchema(field.GoType)
+	description := g.fieldOptions.Description(field)
+	var example any
+	if value, ok := g.fieldOptions.Example(field); ok {
+		example = typeschema.Value[*Schema](schema, value, schemaBuilder{})
+	}
+
+	if location == LocationBody {
+		if op.RequestBody != nil {
+			return fmt.Errorf("field '%s' is a second request body", strings.Join(field.Path, "."))
+		}
+		op.RequestBody = &RequestBody{
+			Description: description,
+			Required:    field.Required,
+			Content: map[string]*MediaType{
+				g.bodyContentType: {
+					Schema:  schema,
+					Example: example,
+				},
+			},
+		}
+		return nil
+	}
+
+	if field.HasDefault {
+		schema.Default = typeschema.Value[*Schema](schema, field.Default, schemaBuilder{})
+	}
+
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        field.TagName,
+		In:          string(location),
+		Description: description,
+		Required:    field.Required || location == LocationPath, // path parameters are always required.
+		Schema:      schema,
+		Example:     example,
+	})
+	return nil
+}
+
+// TypeSchema returns the OpenAPI schema of a Go type. Structs are described using their JSON field names.
+func TypeSchema(typ reflect.Type) *Schema {
+	return typeschema.Walk[*Schema](typ, schemaBuilder{})
+}
+
+// schemaBuilder builds OpenAPI schemas for [typeschema.Walk].
+type schemaBuilder struct{}
+
+func (schemaBuilder) Custom(typ reflect.Type) (*Schema, bool) {
+	return nil, false
+}
+
+func (schemaBuilder) Special(name string) *Schema {
+	switch name {
+	case "date-time", "duration":
+		return &Schema{Type: "string", Format: name}
+	}
+	return &Schema{Type: "string"}
+}
+
+func (schemaBuilder) Primitive(kind reflect.Kind) *Schema {
+	switch kind {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	}
+	return &Schema{Type: "string"}
+}
+
+func (schemaBuilder) Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func (schemaBuilder) Map(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+func (schemaBuilder) Object(properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties}
+}
+
+func (schemaBuilder) Unknown() *Schema {
+	return &Schema{}
+}
+
+func (schemaBuilder) Type(schema *Schema) string {
+	return schema.Type
+}
+
+func (schemaBuilder) Items(schema *Schema) *Schema {
+	return schema.Items
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rrgmc/instruct"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	type Body struct {
+		Name    string            `json:"name"`
+		Tags    []string          `json:"tags,omitempty"`
+		Extra   map[string]string `json:"extra"`
+		Ignored string            `json:"-"`
+	}
+
+	type Paging struct {
+		Page  int `instruct:"query,required=false,default=1"`
+		Limit int `instruct:"query,required=false,desc=Page size,example=20"`
+	}
+
+	type Request struct {
+		ID      int64     `instruct:"path,required=false"`
+		Token   string    `instruct:"header,name=X-Token,desc=Authentication token"`
+		Since   time.Time `instruct:"query,required=false"`
+		Session string    `instruct:"cookie,name=sid"`
+		Paging  Paging    `instruct:"recurse"`
+		Body    Body      `instruct:"body"`
+		Ctx     string    `instruct:"-"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(Request{}))
+	require.NoError(t, err)
+
+	op, err := NewGenerator().Generate(schema)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(op)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"parameters": [
+			{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}},
+			{"name": "X-Token", "in": "header", "description": "Authentication token", "required": true, "schema": {"type": "string"}},
+			{"name": "since", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+			{"name": "sid", "in": "cookie", "required": true, "schema": {"type": "string"}},
+			{"name": "page", "in": "query", "schema": {"type": "integer", "format": "int64", "default": 1}},
+			{"name": "limit", "in": "query", "description": "Page size", "schema": {"type": "integer", "format": "int64"}, "example": 20}
+		],
+		"requestBody": {
+			"required": true,
+			"content": {
+				"application/json": {
+					"schema": {"type": "object", "properties": {
+						"name": {"type": "string"},
+						"tags": {"type": "array", "items": {"type": "string"}},
+						"extra": {"type": "object", "additionalProperties": {"type": "string"}}
+					}}
+				}
+			}
+		}
+	}`, string(data))
+}
+
+func TestGenerateMapTagsAndOperationMapper(t *testing.T) {
+	type Request struct {
+		A string `instruct:"query"`
+		B []int  `instruct:"form"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.SchemaWithMapTags(reflect.TypeOf(Request{}), instruct.MapTags{
+		"A": "header,name=X-A",
+	})
+	require.NoError(t, err)
+
+	op, err := NewGenerator(WithOperationMapper(MapOperations(map[string]Location{
+		"header": LocationHeader,
+		"form":   LocationQuery,
+	}))).Generate(schema)
+	require.NoError(t, err)
+
+	require.Len(t, op.Parameters, 2)
+	require.Equal(t, "X-A", op.Parameters[0].Name)
+	require.Equal(t, "header", op.Parameters[0].In)
+	require.Equal(t, "b", op.Parameters[1].Name)
+	require.Equal(t, "query", op.Parameters[1].In)
+	require.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "integer", Format: "int64"}}, op.Parameters[1].Schema)
+	require.Nil(t, op.RequestBody)
+}
+
+func TestGenerateMultipleBodies(t *testing.T) {
+	type Request struct {
+		A string `instruct:"body"`
+		B string `instruct:"body"`
+	}
+
+	dec := instruct.NewDecoder[*http.Request, instruct.DecodeContext](
+		instruct.NewDefaultOptions[*http.Request, instruct.DecodeContext]())
+
+	schema, err := dec.Schema(reflect.TypeOf(Request{}))
+	require.NoError(t, err)
+
+	_, err = NewGenerator().Generate(schema)
+	require.Error(t, err)
+}
@@ -2,16 +2,20 @@ package instruct
 
 import (
 	"reflect"
+
+	"github.com/rrgmc/instruct/types"
 )
 
 // Default operations.
 const (
-	OperationIgnore  string = "-"
-	OperationRecurse        = "recurse"
+	OperationIgnore      string = "-"
+	OperationRecurse            = "recurse"
+	OperationRecurseList        = "recurse_list" // slice of structs decoded from indexed names, like "items[0].name".
 )
 
 const (
-	mapTagValueKey string = "map-tag"
+	mapTagValueKey       string = "map-tag"
+	defaultListSeparator        = ","
 )
 
 // StructOption "when" values.
@@ -34,15 +38,38 @@ type DecodeOperation[IT any, DC DecodeContext] interface {
 	// ignore the "field" parameter (don't try any kind of conversion). Otherwise, set the "field" value
 	// directly and return IgnoreDecodeValue in "value", for example, when decoding a JSON HTTP body
 	// into a struct field.
-	// If isList is true, try to return an array/slice if available.
+	// If isList is true, try to return an array/slice if available. It is also true for map fields, which
+	// can be resolved from a list of "key=value" strings.
 	Decode(ctx DC, input IT, isList bool, field reflect.Value, tag *Tag) (found bool, value any, err error)
 }
 
+// StructDefaults can be implemented by structs to set computed default values. It is called before the struct
+// fields are decoded, so any value found by the operations overrides the defaults.
+// Fields whose value was changed by it are considered set for required checks.
+type StructDefaults interface {
+	Defaults()
+}
+
 // DecodeOperationValidate allows a DecodeOperation to do a final validation.
 type DecodeOperationValidate[IT any, DC DecodeContext] interface {
 	Validate(ctx DC, input IT) error
 }
 
+// DecodeOperationNames allows a DecodeOperation to list all the value names available in the input. It is
+// required to find the elements of lists using the "recurse_list" operation.
+type DecodeOperationNames[IT any, DC DecodeContext] interface {
+	Names(ctx DC, input IT) ([]string, error)
+}
+
+// DecodeOperationOptionsSpec allows a DecodeOperation to declare the tag options it accepts. If implemented,
+// building the struct info fails for fields using the operation with unknown or malformed options. Options
+// declared by the Resolver and the DefaultOptions.CommonTagOptions are also accepted.
+// A Resolver can implement it to declare its own options. The spec Default is only used for documentation, the
+// operation must apply it when the option is not set.
+type DecodeOperationOptionsSpec interface {
+	OptionsSpec() []types.TagOptionSpec
+}
+
 // DecodeOperationFunc wraps a DecodeOperation as a function.
 type DecodeOperationFunc[IT any, DC DecodeContext] func(ctx DC, input IT, field reflect.Value, typ reflect.Type, tag *Tag) (bool, any, error)
 
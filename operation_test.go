@@ -1,6 +1,7 @@
 package instruct
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -134,6 +135,21 @@ func (d *TestDecodeOperationQuery) Decode(ctx TestDecodeContext, r *http.Request
 	return true, r.URL.Query().Get(tag.Name), nil
 }
 
+func (d *TestDecodeOperationQuery) OptionsSpec() []types.TagOptionSpec {
+	return []types.TagOptionSpec{
+		{Name: "explode", Type: types.TagOptionBool, Default: "true"},
+		{Name: "explodesep", Type: types.TagOptionString},
+	}
+}
+
+func (d *TestDecodeOperationQuery) Names(ctx TestDecodeContext, r *http.Request) ([]string, error) {
+	var names []string
+	for key := range r.URL.Query() {
+		names = append(names, key)
+	}
+	return names, nil
+}
+
 func (d *TestDecodeOperationQuery) Validate(ctx TestDecodeContext, r *http.Request) error {
 	if !ctx.EnsureAllQueryUsed() {
 		return nil
@@ -257,3 +273,119 @@ func (d *TestDecodeOperationManual) Decode(ctx TestDecodeContext, r *http.Reques
 
 	return false, nil, nil
 }
+
+func GetTestEncoderOptions() DefaultEncodeOptions[*http.Request, TestEncodeContext] {
+	optns := NewDefaultEncodeOptions[*http.Request, TestEncodeContext]()
+	optns.EncodeOperations[TestOperationQuery] = &TestEncodeOperationQuery{}
+	optns.EncodeOperations[TestOperationHeader] = &TestEncodeOperationHeader{}
+	optns.EncodeOperations[TestOperationBody] = &TestEncodeOperationBody{}
+	return optns
+}
+
+func GetTestEncoderEncodeOptions(ctx *testEncodeContext) EncodeOptions[*http.Request, TestEncodeContext] {
+	if ctx == nil {
+		ctx = &testEncodeContext{}
+	}
+	if ctx.sliceSplitSeparator == "" {
+		ctx.sliceSplitSeparator = ","
+	}
+	if ctx.DefaultEncodeContext == nil {
+		ec := NewDefaultEncodeContext(DefaultFieldNameMapper)
+		ctx.DefaultEncodeContext = &ec
+	}
+
+	optns := NewEncodeOptions[*http.Request, TestEncodeContext]()
+	optns.Ctx = ctx
+	return optns
+}
+
+type TestEncodeContext interface {
+	EncodeContext
+	SliceSplitSeparator() string
+}
+
+type testEncodeContext struct {
+	*DefaultEncodeContext
+	sliceSplitSeparator string
+}
+
+func (d *testEncodeContext) SliceSplitSeparator() string {
+	return d.sliceSplitSeparator
+}
+
+type TestEncodeOperationQuery struct {
+}
+
+func (d *TestEncodeOperationQuery) Encode(ctx TestEncodeContext, r *http.Request, isList bool, field reflect.Value,
+	value any, tag *Tag) error {
+	q := r.URL.Query()
+
+	switch v := value.(type) {
+	case string:
+		q.Set(tag.Name, v)
+	case []string:
+		explode, err := tag.Options.BoolValue("explode", true)
+		if err != nil {
+			return err
+		}
+
+		q.Del(tag.Name)
+		if explode {
+			q.Set(tag.Name, strings.Join(v, tag.Options.Value("explodesep", ctx.SliceSplitSeparator())))
+		} else {
+			for _, item := range v {
+				q.Add(tag.Name, item)
+			}
+		}
+	default:
+		return fmt.Errorf("cannot encode value of type '%T' as query", value)
+	}
+
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+type TestEncodeOperationHeader struct {
+}
+
+func (d *TestEncodeOperationHeader) Encode(ctx TestEncodeContext, r *http.Request, isList bool, field reflect.Value,
+	value any, tag *Tag) error {
+	switch v := value.(type) {
+	case string:
+		r.Header.Set(tag.Name, v)
+	case []string:
+		r.Header.Del(tag.Name)
+		for _, item := range v {
+			r.Header.Add(tag.Name, item)
+		}
+	default:
+		return fmt.Errorf("cannot encode value of type '%T' as header", value)
+	}
+	return nil
+}
+
+type TestEncodeOperationBody struct {
+}
+
+func (d *TestEncodeOperationBody) Encode(ctx TestEncodeContext, r *http.Request, isList bool, field reflect.Value,
+	value any, tag *Tag) error {
+	var b []byte
+	var err error
+	switch typeStr := tag.Options.Value("type", "json"); typeStr {
+	case "json":
+		b, err = json.Marshal(field.Interface())
+		r.Header.Set("Content-Type", "application/json")
+	case "xml":
+		b, err = xml.Marshal(field.Interface())
+		r.Header.Set("Content-Type", "application/xml")
+	default:
+		return fmt.Errorf("invalid body type: '%s'", typeStr)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.ContentLength = int64(len(b))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/rrgmc/instruct/resolver"
+	"github.com/rrgmc/instruct/types"
 )
 
 // FieldNameMapper maps a struct field name to the target field name.
@@ -17,8 +18,13 @@ type DefaultOptions[IT any, DC DecodeContext] struct {
 	DecodeOperations   map[string]DecodeOperation[IT, DC] // list of decode operations
 	defaultMapTags     *mapTagsList                       // list of DEFAULT map tags
 	FieldNameMapper    FieldNameMapper                    // field name mapper. Default one uses [strings.ToLower].
-	structInfoProvider structInfoProvider[IT, DC]         // allows caching of structInfo
+	NameFromTags       []string                           // other struct tags to get the name from if the "name" option is not set, like "json". Checked in order before FieldNameMapper.
+	structInfoProvider structInfoProvider                 // allows caching of structInfo
 	Resolver           Resolver                           // interface used to convert strings to the struct field type.
+	AggregateErrors    bool                               // whether to decode all fields and return all errors as [types.DecodeErrors] instead of failing on the first one.
+	ListKeyFormat      ListKeyFormat                      // format of the names of list elements for "recurse_list". Default ListKeyFormatBrackets.
+	ListMaxLength      int                                // maximum number of list elements for "recurse_list". Default 1000.
+	CommonTagOptions   []string                           // tag options accepted by all operations implementing DecodeOperationOptionsSpec. Default "desc" and "example".
 }
 
 func (o *DefaultOptions[IT, DC]) DefaultMapTagsSet(t reflect.Type, m MapTags) {
@@ -27,11 +33,50 @@ func (o *DefaultOptions[IT, DC]) DefaultMapTagsSet(t reflect.Type, m MapTags) {
 	o.structInfoProvider.remove(t)
 }
 
+// structInfoOptions returns the options used to build a structInfo.
+func (o *DefaultOptions[IT, DC]) structInfoOptions() structInfoOptions {
+	return structInfoOptions{
+		TagName:         o.TagName,
+		DefaultRequired: o.DefaultRequired,
+		FieldNameMapper: o.FieldNameMapper,
+		NameFromTags:    o.NameFromTags,
+		Resolver:        o.Resolver,
+		OptionsSpec:     o,
+	}
+}
+
+// operationOptionsSpec returns the tag options declared by the operation, if it implements
+// DecodeOperationOptionsSpec.
+func (o *DefaultOptions[IT, DC]) operationOptionsSpec(operation string) ([]types.TagOptionSpec, bool) {
+	os, ok := o.DecodeOperations[operation].(DecodeOperationOptionsSpec)
+	if !ok {
+		return nil, false
+	}
+	return os.OptionsSpec(), true
+}
+
+// optionsSpec returns all the tag options accepted by the operation, if it implements DecodeOperationOptionsSpec.
+// The options of the Resolver and the CommonTagOptions are appended to the operation ones.
+func (o *DefaultOptions[IT, DC]) optionsSpec(operation string) ([]types.TagOptionSpec, bool) {
+	spec, ok := o.operationOptionsSpec(operation)
+	if !ok {
+		return nil, false
+	}
+	ret := append([]types.TagOptionSpec{}, spec...)
+	if ros, ok := o.Resolver.(DecodeOperationOptionsSpec); ok {
+		ret = append(ret, ros.OptionsSpec()...)
+	}
+	for _, name := range o.CommonTagOptions {
+		ret = append(ret, types.TagOptionSpec{Name: name, Type: types.TagOptionString})
+	}
+	return ret, true
+}
+
 func (o *DefaultOptions[IT, DC]) StructInfoCache(cache bool) {
 	if cache {
-		o.structInfoProvider = &cachedStructInfoProvider[IT, DC]{}
+		o.structInfoProvider = &cachedStructInfoProvider{}
 	} else {
-		o.structInfoProvider = &defaultStructInfoProvider[IT, DC]{}
+		o.structInfoProvider = &defaultStructInfoProvider{}
 	}
 }
 
@@ -39,6 +84,7 @@ type DecodeOptions[IT any, DC DecodeContext] struct {
 	Ctx                       DC      // decode context to be sent to DecodeOperation.
 	MapTags                   MapTags // decode call-specific MapTags. They may override existing ones.
 	UseDecodeMapTagsAsDefault bool    // internal flag to allow Decode functions without an instance to set MapTags as a default one.
+	AggregateErrors           *bool   // whether to decode all fields and return all errors as [types.DecodeErrors]. If nil, the DefaultOptions one is used.
 }
 
 type TypeDefaultOptions[IT any, DC DecodeContext] struct {
@@ -54,8 +100,11 @@ func NewDefaultOptions[IT any, DC DecodeContext]() DefaultOptions[IT, DC] {
 		DecodeOperations:   map[string]DecodeOperation[IT, DC]{},
 		defaultMapTags:     &mapTagsList{},
 		FieldNameMapper:    DefaultFieldNameMapper,
-		structInfoProvider: defaultStructInfoProvider[IT, DC]{},
+		structInfoProvider: defaultStructInfoProvider{},
 		Resolver:           resolver.NewResolver(),
+		ListKeyFormat:      ListKeyFormatBrackets,
+		ListMaxLength:      1000,
+		CommonTagOptions:   []string{"desc", "example"},
 	}
 }
 
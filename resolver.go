@@ -1,8 +1,38 @@
 package instruct
 
-import "reflect"
+import (
+	"reflect"
+
+	"github.com/rrgmc/instruct/resolver"
+)
 
 // Resolver converts values to the type of the struct field.
 type Resolver interface {
 	Resolve(target reflect.Value, value any) error
 }
+
+// ResolverWithOptions is an optional Resolver extension that receives the field tag options, for example to
+// configure map separators. [resolver.Resolver] implements it.
+type ResolverWithOptions interface {
+	ResolveOptions(target reflect.Value, value any, options resolver.Options) error
+}
+
+// ResolverFunc is an optional Resolver extension which returns a function specialized for the type of a struct
+// field. It is called once per field when building the struct info, and the function is used on every decode.
+// [resolver.Resolver] implements it.
+type ResolverFunc interface {
+	TypeResolveFunc(typ reflect.Type) resolver.ResolveFunc
+}
+
+// TagResolver is an optional Resolver extension that receives the field Tag, so per-field formats like a time
+// layout or a numeric base can be used. If the Resolver implements it, it is used instead of all other methods.
+type TagResolver interface {
+	ResolveTag(target reflect.Value, value any, tag *Tag) error
+}
+
+// TagResolverFunc is an optional TagResolver extension which returns a function specialized for the type and Tag
+// of a struct field, like ResolverFunc. It is called once per field when building the struct info, so the tag
+// options can be parsed only once. If the Resolver implements it, it is used instead of all other methods.
+type TagResolverFunc interface {
+	TagResolveFunc(typ reflect.Type, tag *Tag) resolver.ResolveFunc
+}
@@ -3,8 +3,28 @@ package resolver
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/rrgmc/instruct/coerce"
+	"github.com/rrgmc/instruct/types"
+)
+
+// Options names.
+const (
+	OptionMapSeparator         = "mapsep"   // separator between map entries, default ",".
+	OptionMapKeyValueSeparator = "mapkvsep" // separator between map keys and values, default "=".
+)
+
+const (
+	DefaultMapSeparator         = ","
+	DefaultMapKeyValueSeparator = "="
 )
 
+// Options gives per-field options to the resolver, usually from the struct tag options.
+type Options interface {
+	Value(name string, defaultValue string) string
+}
+
 // Resolver is the default Resolver.
 type Resolver struct {
 	valueResolver ValueResolver
@@ -32,7 +52,45 @@ func WithValueResolver(valueResolver ValueResolver) Option {
 	}
 }
 
+// OptionsSpec returns the tag options accepted by the resolver.
+func (r Resolver) OptionsSpec() []types.TagOptionSpec {
+	return []types.TagOptionSpec{
+		{
+			Name:        OptionMapSeparator,
+			Type:        types.TagOptionString,
+			Default:     DefaultMapSeparator,
+			Description: "separator between map entries",
+		},
+		{
+			Name:        OptionMapKeyValueSeparator,
+			Type:        types.TagOptionString,
+			Default:     DefaultMapKeyValueSeparator,
+			Description: "separator between map keys and values",
+		},
+	}
+}
+
+// CoercesPrimitives returns whether primitive types are resolved only by the coerce functions, which is true if
+// the ValueResolver is a DefaultValueResolver without custom types. Generated code calls the coerce functions
+// directly only in this case.
+func (r Resolver) CoercesPrimitives() bool {
+	switch vr := r.valueResolver.(type) {
+	case *DefaultValueResolver:
+		return !vr.hasCustomTypes()
+	case DefaultValueResolver:
+		return !vr.hasCustomTypes()
+	}
+	return false
+}
+
 func (r Resolver) Resolve(target reflect.Value, value any) error {
+	return r.ResolveOptions(target, value, nil)
+}
+
+// ResolveOptions resolves the value using per-field options. "options" may be nil.
+// Maps can be resolved from strings in the "k1=v1,k2=v2" format, from slices of "k=v" strings (repeated keys are
+// appended if the map value is a slice), or from other maps. Keys and values are resolved separately.
+func (r Resolver) ResolveOptions(target reflect.Value, value any, options Options) error {
 	// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be check as an array
 	isPrimitive := target.Type().PkgPath() == ""
 
@@ -50,7 +108,7 @@ func (r Resolver) Resolve(target reflect.Value, value any) error {
 		targetSliceValue := reflect.MakeSlice(reflect.SliceOf(elemType), 0, 0)
 		for i := 0; i < sourceValue.Len(); i++ {
 			targetValue := reflect.New(elemType)
-			if err := r.Resolve(targetValue.Elem(), sourceValue.Index(i).Interface()); err != nil {
+			if err := r.ResolveOptions(targetValue.Elem(), sourceValue.Index(i).Interface(), options); err != nil {
 				return err
 			}
 			targetSliceValue = reflect.Append(targetSliceValue, targetValue.Elem())
@@ -74,15 +132,20 @@ func (r Resolver) Resolve(target reflect.Value, value any) error {
 
 		for i := 0; i < sourceValue.Len(); i++ {
 			targetValue := reflect.New(elemType)
-			if err := r.Resolve(targetValue.Elem(), sourceValue.Index(i).Interface()); err != nil {
+			if err := r.ResolveOptions(targetValue.Elem(), sourceValue.Index(i).Interface(), options); err != nil {
 				return err
 			}
 			target.Index(i).Set(targetValue.Elem())
 		}
 		return nil
+	} else if isPrimitive && target.Kind() == reflect.Map {
+		if !target.CanSet() {
+			return fmt.Errorf("cannot set '%s' ", target.Type().Kind())
+		}
+		return r.resolveMap(target, value, options)
 	} else if target.Kind() == reflect.Pointer {
 		ptrValue := reflect.New(target.Type().Elem())
-		if err := r.Resolve(ptrValue.Elem(), value); err != nil {
+		if err := r.ResolveOptions(ptrValue.Elem(), value, options); err != nil {
 			return err
 		}
 		target.Set(ptrValue)
@@ -91,3 +154,180 @@ func (r Resolver) Resolve(target reflect.Value, value any) error {
 
 	return r.valueResolver.ResolveValue(target, value)
 }
+
+// ResolveFunc resolves a value to a target of a specific type.
+type ResolveFunc func(target reflect.Value, value any, options Options) error
+
+// TypeResolveFunc returns a ResolveFunc specialized for the target type, with the same behavior of
+// ResolveOptions. The checks that depend only on the type are done once, and slices are allocated only once.
+func (r Resolver) TypeResolveFunc(typ reflect.Type) ResolveFunc {
+	// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+	isPrimitive := typ.PkgPath() == ""
+
+	if isPrimitive && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		isSlice := typ.Kind() == reflect.Slice
+		elemType := typ.Elem()
+		// named types and custom types may have custom resolvers.
+		elemCopy := elemType.PkgPath() == "" && elemType.Name() != "" && r.CoercesPrimitives()
+		elemResolve := r.TypeResolveFunc(elemType)
+		return func(target reflect.Value, value any, options Options) error {
+			if !target.CanSet() {
+				return fmt.Errorf("cannot set '%s' ", target.Type().Kind())
+			}
+
+			sourceValue := reflect.ValueOf(value)
+
+			if sourceValue.Type().Kind() != reflect.Slice && sourceValue.Type().Kind() != reflect.Array {
+				return fmt.Errorf("expected an array to coerce an array into")
+			}
+
+			if !isSlice {
+				if sourceValue.Len() != target.Len() {
+					return fmt.Errorf("arrays lengths doesn't match")
+				}
+				for i := 0; i < sourceValue.Len(); i++ {
+					if err := elemResolve(target.Index(i), sourceValue.Index(i).Interface(), options); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			targetSliceValue := reflect.MakeSlice(typ, sourceValue.Len(), sourceValue.Len())
+			if sourceValue.Type().Elem() == elemType && elemCopy {
+				// same builtin type, like []string to []string, copy without resolving each item.
+				reflect.Copy(targetSliceValue, sourceValue)
+				target.Set(targetSliceValue)
+				return nil
+			}
+			for i := 0; i < sourceValue.Len(); i++ {
+				if err := elemResolve(targetSliceValue.Index(i), sourceValue.Index(i).Interface(), options); err != nil {
+					return err
+				}
+			}
+			target.Set(targetSliceValue)
+			return nil
+		}
+	} else if isPrimitive && typ.Kind() == reflect.Map {
+		return func(target reflect.Value, value any, options Options) error {
+			if !target.CanSet() {
+				return fmt.Errorf("cannot set '%s' ", target.Type().Kind())
+			}
+			return r.resolveMap(target, value, options)
+		}
+	} else if typ.Kind() == reflect.Pointer {
+		elemType := typ.Elem()
+		elemResolve := r.TypeResolveFunc(elemType)
+		return func(target reflect.Value, value any, options Options) error {
+			ptrValue := reflect.New(elemType)
+			if err := elemResolve(ptrValue.Elem(), value, options); err != nil {
+				return err
+			}
+			target.Set(ptrValue)
+			return nil
+		}
+	}
+
+	if vr, ok := r.valueResolver.(ValueResolverFunc); ok {
+		valueResolve := vr.TypeResolveValueFunc(typ)
+		return func(target reflect.Value, value any, options Options) error {
+			return valueResolve(target, value)
+		}
+	}
+	return func(target reflect.Value, value any, options Options) error {
+		return r.valueResolver.ResolveValue(target, value)
+	}
+}
+
+// resolveMap resolves a map from a string, a slice of strings, or another map.
+func (r Resolver) resolveMap(target reflect.Value, value any, options Options) error {
+	targetMap := reflect.MakeMap(target.Type())
+	keyType := target.Type().Key()
+	elemType := target.Type().Elem()
+
+	setEntry := func(key any, value any) error {
+		keyValue := reflect.New(keyType).Elem()
+		if err := r.ResolveOptions(keyValue, key, options); err != nil {
+			return err
+		}
+		elemValue := reflect.New(elemType).Elem()
+		if err := r.ResolveOptions(elemValue, value, options); err != nil {
+			return err
+		}
+		targetMap.SetMapIndex(keyValue, elemValue)
+		return nil
+	}
+
+	sourceValue := reflect.ValueOf(value)
+	switch sourceValue.Kind() {
+	case reflect.Map:
+		iter := sourceValue.MapRange()
+		for iter.Next() {
+			if err := setEntry(iter.Key().Interface(), iter.Value().Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.String, reflect.Slice, reflect.Array:
+		entrySeparator := optionValue(options, OptionMapSeparator, DefaultMapSeparator)
+		kvSeparator := optionValue(options, OptionMapKeyValueSeparator, DefaultMapKeyValueSeparator)
+
+		var entries []string
+		if sourceValue.Kind() == reflect.String {
+			entries = strings.Split(sourceValue.String(), entrySeparator)
+		} else {
+			for i := 0; i < sourceValue.Len(); i++ {
+				s, err := coerce.String(sourceValue.Index(i).Interface())
+				if err != nil {
+					return err
+				}
+				entries = append(entries, strings.Split(s, entrySeparator)...)
+			}
+		}
+
+		// group values by key, keeping the source order.
+		var keys []string
+		keyValues := map[string][]string{}
+		for _, entry := range entries {
+			if entry == "" {
+				continue
+			}
+			k, v, found := strings.Cut(entry, kvSeparator)
+			if !found {
+				return fmt.Errorf("%w: map entry '%s' has no key/value separator '%s'",
+					types.ErrCoerceInvalid, entry, kvSeparator)
+			}
+			if _, ok := keyValues[k]; !ok {
+				keys = append(keys, k)
+			}
+			keyValues[k] = append(keyValues[k], v)
+		}
+
+		// only check slices/arrays for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+		elemIsList := elemType.PkgPath() == "" && (elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array)
+		for _, k := range keys {
+			var err error
+			if elemIsList {
+				err = setEntry(k, keyValues[k])
+			} else {
+				// the last value wins
+				err = setEntry(k, keyValues[k][len(keyValues[k])-1])
+			}
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: cannot coerce source of type '%T' into a map", types.ErrCoerceUnsupported, value)
+	}
+
+	target.Set(targetMap)
+	return nil
+}
+
+// optionValue returns an option value, or the default value if options is nil.
+func optionValue(options Options, name string, defaultValue string) string {
+	if options == nil {
+		return defaultValue
+	}
+	return options.Value(name, defaultValue)
+}
@@ -3,9 +3,12 @@ package resolver
 import (
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/rrgmc/instruct/coerce"
+	"github.com/rrgmc/instruct/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -226,3 +229,165 @@ func Test_resolve_textUnmarshaller(t *testing.T) {
 	// require.NoError(t, err)
 	// require.Equal(t, t1, target.Interface())
 }
+
+type testOptions map[string]string
+
+func (o testOptions) Value(name string, defaultValue string) string {
+	if v, ok := o[name]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+func Test_resolveMap(t *testing.T) {
+	resolver := NewResolver()
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		value   any
+		options Options
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "resolve string", input: map[string]string{}, value: "k1=v1,k2=v2", want: map[string]string{"k1": "v1", "k2": "v2"}},
+		{name: "resolve empty string", input: map[string]string{}, value: "", want: map[string]string{}},
+		{name: "resolve int values", input: map[string]int{}, value: "k1=1,k2=2", want: map[string]int{"k1": 1, "k2": 2}},
+		{name: "resolve int keys", input: map[int]bool{}, value: "1=true,2=false", want: map[int]bool{1: true, 2: false}},
+		{name: "resolve last value wins", input: map[string]string{}, value: "k1=a,k1=b", want: map[string]string{"k1": "b"}},
+		{name: "resolve slice values", input: map[string][]int{}, value: "k1=1,k1=2,k2=3", want: map[string][]int{"k1": {1, 2}, "k2": {3}}},
+		{name: "resolve from slice", input: map[string]string{}, value: []string{"k1=v1", "k2=v2,k3=v3"}, want: map[string]string{"k1": "v1", "k2": "v2", "k3": "v3"}},
+		{name: "resolve from map", input: map[string]int{}, value: map[string]any{"k1": "1", "k2": 2}, want: map[string]int{"k1": 1, "k2": 2}},
+		{name: "resolve from map of slices", input: map[string][]string{}, value: map[string][]string{"k1": {"a", "b"}}, want: map[string][]string{"k1": {"a", "b"}}},
+		{name: "resolve with separators", input: map[string]string{}, value: "k1:v1;k2:v2",
+			options: testOptions{OptionMapSeparator: ";", OptionMapKeyValueSeparator: ":"}, want: map[string]string{"k1": "v1", "k2": "v2"}},
+		{name: "failed missing separator", input: map[string]string{}, value: "k1", wantErr: true},
+		{name: "failed value type", input: map[string]int{}, value: "k1=x", wantErr: true},
+		{name: "failed unsupported source", input: map[string]int{}, value: 12, wantErr: true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			f := reflect.New(reflect.TypeOf(tt.input)).Elem()
+			err := resolver.ResolveOptions(f, tt.value, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				require.Equal(t, tt.want, f.Interface())
+			}
+		})
+	}
+}
+
+func Test_typeResolveFunc(t *testing.T) {
+	resolver := NewResolver(WithValueResolver(NewDefaultValueResolver(WithCustomTypes(NewValueResolverTime(time.RFC3339)))))
+
+	tests := []struct {
+		name  string
+		input interface{}
+		value interface{}
+	}{
+		{name: "resolve string", input: "", value: "test"},
+		{name: "resolve int", input: int(0), value: "12"},
+		{name: "resolve uint16", input: uint16(0), value: "12"},
+		{name: "resolve float", input: float32(0), value: "1.5"},
+		{name: "resolve bool", input: false, value: "true"},
+		{name: "resolve pointer", input: (*int)(nil), value: "12"},
+		{name: "resolve []int", input: []int{}, value: []string{"1", "2"}},
+		{name: "resolve [2]int", input: [2]int{}, value: []string{"1", "2"}},
+		{name: "resolve []string", input: []string{}, value: []string{"a", "b"}},
+		{name: "resolve map", input: map[string]int{}, value: "a=1,b=2"},
+		{name: "resolve time", input: time.Time{}, value: "2023-05-01T10:00:00Z"},
+		{name: "failed int", input: int(0), value: "x"},
+		{name: "failed []int", input: []int{}, value: []string{"1", "x"}},
+		{name: "failed [2]int", input: [2]int{}, value: []string{"1"}},
+		{name: "failed unsupported type", input: struct{}{}, value: "trick"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			typ := reflect.TypeOf(tt.input)
+
+			want := reflect.New(typ).Elem()
+			wantErr := resolver.ResolveOptions(want, tt.value, nil)
+
+			got := reflect.New(typ).Elem()
+			gotErr := resolver.TypeResolveFunc(typ)(got, tt.value, nil)
+
+			require.Equal(t, wantErr, gotErr)
+			require.Equal(t, want.Interface(), got.Interface())
+		})
+	}
+}
+
+// upperStringValueResolver resolves strings in upper case.
+type upperStringValueResolver struct{}
+
+func (u upperStringValueResolver) ResolveTypeValue(target reflect.Value, value any) error {
+	s, ok := value.(string)
+	if !ok || target.Kind() != reflect.String {
+		return types.ErrCoerceUnknown
+	}
+	target.SetString(strings.ToUpper(s))
+	return nil
+}
+
+func Test_typeResolveFunc_sliceCopy(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver *Resolver
+		want     []string
+	}{
+		{name: "without custom types", resolver: NewResolver(), want: []string{"a", "b"}},
+		{name: "with custom types", resolver: NewResolver(WithValueResolver(NewDefaultValueResolver(
+			WithCustomTypes(upperStringValueResolver{})))), want: []string{"A", "B"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			var want, got []string
+			require.NoError(t, tt.resolver.ResolveOptions(reflect.ValueOf(&want).Elem(), []string{"a", "b"}, nil))
+			require.NoError(t, tt.resolver.TypeResolveFunc(reflect.TypeOf(got))(reflect.ValueOf(&got).Elem(),
+				[]string{"a", "b"}, nil))
+			require.Equal(t, tt.want, want)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func Test_resolveTimeWithOptions(t *testing.T) {
+	resolver := NewResolver(WithValueResolver(NewDefaultValueResolver(WithCustomTypes(
+		NewValueResolverTimeWithOptions(coerce.TimeOptions{
+			Layouts: []string{time.RFC3339, time.DateOnly},
+			Epoch:   coerce.TimeEpochMilliseconds,
+		})))))
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    time.Time
+		wantErr string
+	}{
+		{name: "resolve layout", value: "2023-05-01T10:00:00Z", want: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "resolve second layout", value: "2023-05-01", want: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "resolve epoch string", value: "1682935200000", want: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "resolve epoch int", value: int64(1682935200000), want: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "failed", value: "01/05/2023",
+			wantErr: `could not parse "01/05/2023" as time.Time using "2006-01-02T15:04:05Z07:00", "2006-01-02", epoch milliseconds`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Time
+			f := reflect.ValueOf(&got).Elem()
+			err := resolver.Resolve(f, []interface{}{tt.value})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []time.Time{tt.want}, got)
+		})
+	}
+}
@@ -10,12 +10,20 @@ import (
 
 // ValueResolverTime resolves time.Time values.
 type ValueResolverTime struct {
-	layout string
+	options coerce.TimeOptions
 }
 
 func NewValueResolverTime(layout string) *ValueResolverTime {
+	return NewValueResolverTimeWithOptions(coerce.TimeOptions{
+		Layouts: []string{layout},
+	})
+}
+
+// NewValueResolverTimeWithOptions creates a ValueResolverTime that tries multiple layouts and can parse Unix
+// epoch values.
+func NewValueResolverTimeWithOptions(options coerce.TimeOptions) *ValueResolverTime {
 	return &ValueResolverTime{
-		layout: layout,
+		options: options,
 	}
 }
 
@@ -23,7 +31,7 @@ func (d *ValueResolverTime) ResolveTypeValue(target reflect.Value, value any) er
 	if target.CanInterface() {
 		switch target.Interface().(type) {
 		case time.Time:
-			c, err := coerce.Time(value, d.layout)
+			c, err := coerce.TimeWithOptions(value, d.options)
 			target.Set(reflect.ValueOf(c))
 			return err
 		}
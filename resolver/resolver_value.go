@@ -75,6 +75,50 @@ func WithCustomTypesReflect(customTypes ...TypeValueResolverReflect) ValueOption
 	}
 }
 
+// hasCustomTypes returns whether any custom type was added.
+func (r DefaultValueResolver) hasCustomTypes() bool {
+	return len(r.CustomTypes) > 0 || len(r.CustomTypesReflect) > 0
+}
+
+// ValueResolverFunc is an optional ValueResolver interface which returns a function specialized for a target
+// type, to avoid checks that depend only on the type on every call.
+type ValueResolverFunc interface {
+	TypeResolveValueFunc(typ reflect.Type) ValueResolveFunc
+}
+
+// ValueResolveFunc resolves a value to a target of a specific type.
+type ValueResolveFunc func(target reflect.Value, value any) error
+
+// TypeResolveValueFunc returns a ValueResolveFunc for the target type. Primitive types call the coerce function
+// directly, after checking the custom types.
+func (r DefaultValueResolver) TypeResolveValueFunc(typ reflect.Type) ValueResolveFunc {
+	primitive := primitiveResolveValueFunc(typ.Kind())
+	if primitive == nil {
+		return r.ResolveValue
+	}
+	return func(target reflect.Value, value any) error {
+		if !target.CanSet() {
+			return types.NewCoerceError(fmt.Errorf("cannot set '%s' ", target.Type().Kind()))
+		}
+		if len(r.CustomTypes) > 0 && target.CanInterface() {
+			for _, customType := range r.CustomTypes {
+				err := customType.ResolveTypeValue(target, value)
+				if err == nil {
+					return nil
+				}
+				if errors.Is(err, types.ErrCoerceUnknown) {
+					continue
+				}
+				return types.NewCoerceError(err)
+			}
+		}
+		if err := primitive(target, value); err != nil {
+			return types.NewCoerceError(err)
+		}
+		return nil
+	}
+}
+
 func (r DefaultValueResolver) ResolveValue(target reflect.Value, value any) error {
 	err := r.resolveValue(target, value)
 	if err != nil {
@@ -103,63 +147,8 @@ func (r DefaultValueResolver) resolveValue(target reflect.Value, value any) erro
 	}
 
 	// resolve primitive types without reflection
-	switch target.Type().Kind() {
-	case reflect.Bool:
-		c, err := coerce.Bool(value)
-		target.SetBool(c)
-		return err
-	case reflect.Float32:
-		c, err := coerce.Float32(value)
-		target.SetFloat(float64(c))
-		return err
-	case reflect.Float64:
-		c, err := coerce.Float64(value)
-		target.SetFloat(c)
-		return err
-	case reflect.Int:
-		c, err := coerce.Int(value)
-		target.SetInt(int64(c))
-		return err
-	case reflect.Int8:
-		c, err := coerce.Int8(value)
-		target.SetInt(int64(c))
-		return err
-	case reflect.Int16:
-		c, err := coerce.Int16(value)
-		target.SetInt(int64(c))
-		return err
-	case reflect.Int32:
-		c, err := coerce.Int32(value)
-		target.SetInt(int64(c))
-		return err
-	case reflect.Int64:
-		c, err := coerce.Int64(value)
-		target.SetInt(int64(c))
-		return err
-	case reflect.Uint:
-		c, err := coerce.Uint(value)
-		target.SetUint(uint64(c))
-		return err
-	case reflect.Uint8:
-		c, err := coerce.Uint8(value)
-		target.SetUint(uint64(c))
-		return err
-	case reflect.Uint16:
-		c, err := coerce.Uint16(value)
-		target.SetUint(uint64(c))
-		return err
-	case reflect.Uint32:
-		c, err := coerce.Uint32(value)
-		target.SetUint(uint64(c))
-		return err
-	case reflect.Uint64:
-		c, err := coerce.Uint64(value)
-		target.SetUint(uint64(c))
-		return err
-	case reflect.String:
-		c, err := coerce.String(value)
-		target.SetString(c)
-		return err
+	if primitive := primitiveResolveValueFunc(target.Type().Kind()); primitive != nil {
+		return primitive(target, value)
 	}
 
 	// resolve using reflection
@@ -196,3 +185,95 @@ func (r DefaultValueResolver) resolveValue(target reflect.Value, value any) erro
 	return fmt.Errorf("%w: cannot coerce source of type '%T' into target of type '%s'",
 		types.ErrCoerceUnknown, value, target.Type().Kind())
 }
+
+// primitiveResolveValueFunc returns the function to resolve a primitive type kind without reflection, or nil if
+// the kind is not primitive.
+func primitiveResolveValueFunc(kind reflect.Kind) ValueResolveFunc {
+	switch kind {
+	case reflect.Bool:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Bool(value)
+			target.SetBool(c)
+			return err
+		}
+	case reflect.Float32:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Float32(value)
+			target.SetFloat(float64(c))
+			return err
+		}
+	case reflect.Float64:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Float64(value)
+			target.SetFloat(c)
+			return err
+		}
+	case reflect.Int:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Int(value)
+			target.SetInt(int64(c))
+			return err
+		}
+	case reflect.Int8:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Int8(value)
+			target.SetInt(int64(c))
+			return err
+		}
+	case reflect.Int16:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Int16(value)
+			target.SetInt(int64(c))
+			return err
+		}
+	case reflect.Int32:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Int32(value)
+			target.SetInt(int64(c))
+			return err
+		}
+	case reflect.Int64:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Int64(value)
+			target.SetInt(c)
+			return err
+		}
+	case reflect.Uint:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Uint(value)
+			target.SetUint(uint64(c))
+			return err
+		}
+	case reflect.Uint8:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Uint8(value)
+			target.SetUint(uint64(c))
+			return err
+		}
+	case reflect.Uint16:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Uint16(value)
+			target.SetUint(uint64(c))
+			return err
+		}
+	case reflect.Uint32:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Uint32(value)
+			target.SetUint(uint64(c))
+			return err
+		}
+	case reflect.Uint64:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.Uint64(value)
+			target.SetUint(c)
+			return err
+		}
+	case reflect.String:
+		return func(target reflect.Value, value any) error {
+			c, err := coerce.String(value)
+			target.SetString(c)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rrgmc/instruct/types"
+)
+
+// Schema describes what a struct type decodes from the input, after MapTags are applied.
+// It can be marshaled to JSON.
+type Schema struct {
+	Type         string         `json:"type"`                   // Go type of the struct.
+	GoType       reflect.Type   `json:"-"`                      // Go type of the struct.
+	StructOption *SchemaField   `json:"structOption,omitempty"` // struct option of the struct, if set.
+	Fields       []*SchemaField `json:"fields,omitempty"`       // struct fields, in declaration order.
+}
+
+// SchemaField describes a struct field, or a struct option.
+type SchemaField struct {
+	Name           string            `json:"name"`                     // struct field name, blank for struct options.
+	Embedded       bool              `json:"embedded,omitempty"`       // whether the struct field is embedded.
+	Path           []string          `json:"path"`                     // complete field path, using the struct field names.
+	Type           string            `json:"type"`                     // Go type of the field.
+	GoType         reflect.Type      `json:"-"`                        // Go type of the field.
+	Operation      string            `json:"operation"`                // decode operation.
+	TagName        string            `json:"tagName"`                  // data name, like the header or query param name.
+	Required       bool              `json:"required"`                 // whether the field is required.
+	Default        string            `json:"default,omitempty"`        // default value, if HasDefault is true.
+	HasDefault     bool              `json:"hasDefault,omitempty"`     // whether a default value was set.
+	Options        map[string]string `json:"options,omitempty"`        // all the tag options.
+	IsStructOption bool              `json:"isStructOption,omitempty"` // whether this is a struct option.
+	SOWhen         string            `json:"soWhen,omitempty"`         // struct options: when to decode (before or after the fields).
+	SORecurse      bool              `json:"soRecurse,omitempty"`      // struct options: whether to recurse into the inner struct.
+	Struct         *Schema           `json:"struct,omitempty"`         // inner struct for "recurse", or list element struct for "recurse_list".
+	MapTagsRule    string            `json:"mapTagsRule,omitempty"`    // index and description of the MapTagsRule that produced the tag, if any.
+	// OptionsSpec are the tag options declared by the operation, if it implements DecodeOperationOptionsSpec.
+	OptionsSpec []types.TagOptionSpec `json:"optionsSpec,omitempty"`
+}
+
+// String returns the schema in a human-readable indented format.
+func (s *Schema) String() string {
+	var b strings.Builder
+	s.writeIndent(&b, "")
+	return b.String()
+}
+
+func (s *Schema) writeIndent(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s%s\n", indent, s.Type)
+	if s.StructOption != nil {
+		s.StructOption.writeIndent(b, indent+"\t")
+	}
+	for _, field := range s.Fields {
+		field.writeIndent(b, indent+"\t")
+	}
+}
+
+func (f *SchemaField) writeIndent(b *strings.Builder, indent string) {
+	name := f.Name
+	if f.IsStructOption && name == "" {
+		name = StructOptionMapTag
+	}
+	fmt.Fprintf(b, "%s- %s (%s): %s", indent, name, f.Type, f.Operation)
+	if f.Operation != OperationIgnore && f.Operation != OperationRecurse {
+		fmt.Fprintf(b, " name=%s required=%t", f.TagName, f.Required)
+	}
+	if f.HasDefault {
+		fmt.Fprintf(b, " default=%s", f.Default)
+	}
+	var optionNames []string
+	for name := range f.Options {
+		optionNames = append(optionNames, name)
+	}
+	sort.Strings(optionNames)
+	for _, name := range optionNames {
+		fmt.Fprintf(b, " %s=%s", name, f.Options[name])
+	}
+	if f.IsStructOption {
+		fmt.Fprintf(b, " so_when=%s so_recurse=%t", f.SOWhen, f.SORecurse)
+	}
+	if f.MapTagsRule != "" {
+		fmt.Fprintf(b, " [map tags rule %s]", f.MapTagsRule)
+	}
+	b.WriteString("\n")
+	if f.Struct != nil {
+		f.Struct.writeIndent(b, indent+"\t")
+	}
+}
+
+// Schema returns the schema of a struct type, using the default MapTags of the type.
+func (d *Decoder[IT, DC]) Schema(typ reflect.Type) (*Schema, error) {
+	return d.SchemaWithMapTags(typ, nil)
+}
+
+// SchemaWithMapTags returns the schema of a struct type, applying the MapTags over the default ones like a
+// decode call with [DecodeOptions.MapTags] would.
+func (d *Decoder[IT, DC]) SchemaWithMapTags(typ reflect.Type, mapTags MapTags) (*Schema, error) {
+	si, err := d.structInfoFromType(typ)
+	if err != nil {
+		return nil, err
+	}
+	if mapTags != nil {
+		si, err = structInfoWithMapTags(si, mapTags, d.options.structInfoOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schemaFromStructInfo(si, d.options.operationOptionsSpec), nil
+}
+
+// Schema returns the schema of the decoder type.
+func (d *TypeDecoder[IT, DC, T]) Schema() (*Schema, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return schemaFromStructInfo(d.si, d.decoder.options.operationOptionsSpec), nil
+}
+
+// schemaFromStructInfo builds a Schema from a structInfo of a struct. optionsSpec may be nil.
+func schemaFromStructInfo(si *structInfo, optionsSpec func(operation string) ([]types.TagOptionSpec, bool)) *Schema {
+	ret := &Schema{
+		Type:   si.typ.String(),
+		GoType: si.typ,
+	}
+	if si.tag != nil && si.tag.IsSO {
+		ret.StructOption = schemaFieldFromStructInfo(si, optionsSpec)
+	}
+	for _, field := range si.fields {
+		ret.Fields = append(ret.Fields, schemaFieldFromStructInfo(field, optionsSpec))
+	}
+	return ret
+}
+
+// schemaFieldFromStructInfo builds a SchemaField from a structInfo of a field or struct option.
+func schemaFieldFromStructInfo(si *structInfo, optionsSpec func(operation string) ([]types.TagOptionSpec, bool)) *SchemaField {
+	ret := &SchemaField{
+		Name:        si.field.Name,
+		Embedded:    si.field.Anonymous,
+		Path:        append([]string{}, si.path...),
+		Operation:   si.tag.Operation,
+		TagName:     si.tag.Name,
+		Required:    si.tag.Required,
+		Default:     si.tag.Default,
+		HasDefault:  si.tag.HasDefault,
+		MapTagsRule: si.tag.mapTagsRule,
+	}
+	if si.field.Type != nil {
+		ret.GoType = si.field.Type
+	} else {
+		ret.GoType = si.typ
+	}
+	ret.Type = ret.GoType.String()
+	if len(si.tag.Options.options) > 0 {
+		ret.Options = map[string]string{}
+		for name, value := range si.tag.Options.options {
+			ret.Options[name] = value
+		}
+	}
+	if si.tag.IsSO {
+		ret.IsStructOption = true
+		ret.SOWhen = soOptionValue(si.tag.SOWhen)
+		ret.SORecurse = si.tag.SORecurse
+	}
+	switch si.tag.Operation {
+	case OperationIgnore:
+	case OperationRecurse:
+		ret.Struct = schemaFromStructInfo(si, optionsSpec)
+	case OperationRecurseList:
+		ret.Struct = schemaFromStructInfo(si.elem, optionsSpec)
+	default:
+		if optionsSpec != nil {
+			ret.OptionsSpec, _ = optionsSpec(si.tag.Operation)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,103 @@
+package instruct
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	type Inner struct {
+		X int `instruct:"header,name=X-Value,required=false"`
+	}
+
+	type Item struct {
+		Name string `instruct:"query"`
+	}
+
+	type DataType struct {
+		_     StructOption `instruct:"body,so_when=after,so_recurse=true"`
+		A     string       `instruct:"query,explode=false,default=x"`
+		B     []int        `instruct:"header"`
+		Inner Inner        `instruct:"recurse"`
+		Items []Item       `instruct:"recurse_list,required=false"`
+		Ign   string       `instruct:"-"`
+	}
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+
+	schema, err := dec.Schema(reflect.TypeOf(&DataType{}))
+	require.NoError(t, err)
+
+	require.NotNil(t, schema.StructOption)
+	require.Equal(t, "body", schema.StructOption.Operation)
+	require.Equal(t, SOOptionWhenAfter, schema.StructOption.SOWhen)
+	require.Len(t, schema.Fields, 5)
+	require.Equal(t, reflect.TypeOf([]int{}), schema.Fields[1].GoType)
+	require.Equal(t, []string{"Inner", "X"}, schema.Fields[2].Struct.Fields[0].Path)
+	require.Equal(t, "X-Value", schema.Fields[2].Struct.Fields[0].TagName)
+	require.Equal(t, []string{"Items", "Name"}, schema.Fields[3].Struct.Fields[0].Path)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "instruct.DataType",
+		"structOption": {"name": "", "path": [], "type": "instruct.DataType", "operation": "body", "tagName": "_",
+			"required": true, "isStructOption": true, "soWhen": "after", "soRecurse": true},
+		"fields": [
+			{"name": "A", "path": ["A"], "type": "string", "operation": "query", "tagName": "a", "required": true,
+				"default": "x", "hasDefault": true, "options": {"explode": "false"},
+				"optionsSpec": [{"name": "explode", "type": "bool", "default": "true"}, {"name": "explodesep", "type": "string"}]},
+			{"name": "B", "path": ["B"], "type": "[]int", "operation": "header", "tagName": "b", "required": true},
+			{"name": "Inner", "path": ["Inner"], "type": "instruct.Inner", "operation": "recurse", "tagName": "inner",
+				"required": true, "struct": {"type": "instruct.Inner", "fields": [
+					{"name": "X", "path": ["Inner", "X"], "type": "int", "operation": "header", "tagName": "X-Value", "required": false}
+				]}},
+			{"name": "Items", "path": ["Items"], "type": "[]instruct.Item", "operation": "recurse_list", "tagName": "items",
+				"required": false, "struct": {"type": "instruct.Item", "fields": [
+					{"name": "Name", "path": ["Items", "Name"], "type": "string", "operation": "query", "tagName": "name", "required": true,
+						"optionsSpec": [{"name": "explode", "type": "bool", "default": "true"}, {"name": "explodesep", "type": "string"}]}
+				]}},
+			{"name": "Ign", "path": ["Ign"], "type": "string", "operation": "-", "tagName": "ign", "required": true}
+		]
+	}`, string(data))
+}
+
+func TestSchemaMapTags(t *testing.T) {
+	type DataType struct {
+		A string
+		B string `instruct:"query"`
+	}
+
+	defOpt := GetTestDecoderOptions()
+	defOpt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), MapTags{
+		"A": "header",
+	})
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](defOpt)
+
+	schema, err := dec.SchemaWithMapTags(reflect.TypeOf(DataType{}), MapTags{
+		"B": "header,name=bb",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "header", schema.Fields[0].Operation)
+	require.Equal(t, "header", schema.Fields[1].Operation)
+	require.Equal(t, "bb", schema.Fields[1].TagName)
+}
+
+func TestTypeDecoderSchema(t *testing.T) {
+	type DataType struct {
+		A string `instruct:"query"`
+	}
+
+	dec := NewTypeDecoder[*http.Request, TestDecodeContext, DataType](GetTestTypeDecoderOptions())
+
+	schema, err := dec.Schema()
+	require.NoError(t, err)
+	require.Len(t, schema.Fields, 1)
+	require.Equal(t, "query", schema.Fields[0].Operation)
+	require.Contains(t, schema.String(), "- A (string): query name=a required=true")
+}
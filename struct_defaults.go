@@ -0,0 +1,48 @@
+package instruct
+
+import "reflect"
+
+// StructDefaultsSnapshot is the value of a struct before [StructDefaults.Defaults] was called, used to check
+// which fields were set by it. A nil snapshot means that the struct doesn't implement StructDefaults.
+type StructDefaultsSnapshot struct {
+	before reflect.Value
+}
+
+// callStructDefaults calls [StructDefaults.Defaults] if the struct value implements it, and returns the snapshot
+// of the struct before the call.
+func callStructDefaults(dataValue reflect.Value) *StructDefaultsSnapshot {
+	if !dataValue.CanAddr() {
+		return nil
+	}
+	sd, ok := dataValue.Addr().Interface().(StructDefaults)
+	if !ok {
+		return nil
+	}
+	before := reflect.New(dataValue.Type()).Elem()
+	before.Set(dataValue)
+	sd.Defaults()
+	return &StructDefaultsSnapshot{before: before}
+}
+
+// FieldSet returns whether the field with the name was changed by [StructDefaults.Defaults]. value is the
+// current value of the field.
+func (s *StructDefaultsSnapshot) FieldSet(name string, value reflect.Value) bool {
+	if s == nil {
+		return false
+	}
+	return valueChanged(s.before.FieldByName(name), value)
+}
+
+// fieldSet is like FieldSet using the field index.
+func (s *StructDefaultsSnapshot) fieldSet(index []int, value reflect.Value) bool {
+	if s == nil {
+		return false
+	}
+	return valueChanged(s.before.FieldByIndex(index), value)
+}
+
+// valueChanged returns whether the values are different.
+// Interfaces can hold slices, maps or funcs, where [reflect.Value.Equal] panics, so reflect.DeepEqual is used.
+func valueChanged(before, after reflect.Value) bool {
+	return !reflect.DeepEqual(before.Interface(), after.Interface())
+}
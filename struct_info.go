@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -15,12 +16,19 @@ type structInfo struct {
 	tag    *Tag                // tag
 	path   []string            // complete field path including itself, using the unmodified struct field name
 	fields []*structInfo       // child fields
+	elem   *structInfo         // list element, only for "recurse_list"
+	plan   *fieldPlan          // precompiled decode plan, nil on root struct
 }
 
 func (s *structInfo) fullFieldName() string {
 	return strings.Join(s.path, ".")
 }
 
+// isStructOperation returns whether the struct has a struct option to be executed at "when".
+func (s *structInfo) isStructOperation(when string) bool {
+	return s.tag != nil && s.tag.IsSO && soOptionValue(s.tag.SOWhen) == when
+}
+
 func (s *structInfo) checkSameType(t reflect.Type) error {
 	t = reflectElem(t)
 	if t != s.typ {
@@ -47,6 +55,52 @@ func (s *structInfo) fieldByName(name string) *structInfo {
 	return nil
 }
 
+// withListIndex returns a copy of a list element structInfo for a specific element index. The index is inserted
+// in the field paths at position pathIndex. If rename is true, the tag names are formatted using the ListKeyFormat.
+// Inner lists elements are never renamed, as their names are formatted from the inner list name.
+func (s *structInfo) withListIndex(pathIndex int, listName string, index int, keyFormat ListKeyFormat, rename bool) *structInfo {
+	ret := &structInfo{
+		typ:   s.typ,
+		field: s.field,
+		tag:   s.tag,
+		path:  s.path,
+		plan:  s.plan,
+	}
+	if len(s.path) >= pathIndex {
+		ret.path = append(append(append([]string{}, s.path[:pathIndex]...), strconv.Itoa(index)), s.path[pathIndex:]...)
+	}
+	if rename && s.tag != nil && s.field.Type != nil {
+		tag := *s.tag
+		tag.Name = keyFormat.Name(listName, index, tag.Name)
+		ret.tag = &tag
+	}
+	if s.elem != nil {
+		ret.elem = s.elem.withListIndex(pathIndex, listName, index, keyFormat, false)
+	}
+	for _, field := range s.fields {
+		ret.fields = append(ret.fields, field.withListIndex(pathIndex, listName, index, keyFormat, rename))
+	}
+	return ret
+}
+
+// collectOperations adds all the operations used by the fields to the map.
+func (s *structInfo) collectOperations(operations map[string]bool) {
+	if s.tag != nil && s.tag.IsSO {
+		operations[s.tag.Operation] = true
+	}
+	for _, field := range s.fields {
+		switch field.tag.Operation {
+		case OperationIgnore:
+		case OperationRecurse:
+			field.collectOperations(operations)
+		case OperationRecurseList:
+			field.elem.collectOperations(operations)
+		default:
+			operations[field.tag.Operation] = true
+		}
+	}
+}
+
 func (s *structInfo) dump(w io.Writer) error {
 	return s.dumpIndent("", w)
 }
@@ -73,19 +127,29 @@ func (s *structInfo) dumpIndent(indent string, w io.Writer) error {
 	for _, field := range s.fields {
 		ferr = errors.Join(ferr, field.dumpIndent(indent+"\t", w))
 	}
+	if s.elem != nil {
+		ferr = errors.Join(ferr, s.elem.dumpIndent(indent+"\t", w))
+	}
 	return err
 }
 
 // structInfoWithMapTags overrides a structInfo with a MapTags. This creates a clone of all the objects and don't
 // change the original in any way.
-func structInfoWithMapTags[IT any, DC DecodeContext](si *structInfo, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error) {
+func structInfoWithMapTags(si *structInfo, mapTags MapTags, options structInfoOptions) (*structInfo, error) {
 	ctx := &buildContext{
 		clone:            true,
 		skipStructField:  true,
 		skipStructOption: true,
 	}
 
+	if err := mapTags.checkRules(); err != nil {
+		return nil, err
+	}
+
 	newsi, err := buildStructInfoItem(ctx, si, level{}, mapTags, options)
+	if err == nil {
+		err = ctx.err()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +159,10 @@ func structInfoWithMapTags[IT any, DC DecodeContext](si *structInfo, mapTags Map
 		if err != nil {
 			return nil, err
 		}
+		err = mapTags.checkUnusedRules(ctx.GetUsedValues(mapTagRuleValueKey))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return newsi, nil
@@ -5,11 +5,15 @@ import (
 	"reflect"
 )
 
-func buildStructInfo[IT any, DC DecodeContext](t reflect.Type, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error) {
+func buildStructInfo(t reflect.Type, mapTags MapTags, options structInfoOptions) (*structInfo, error) {
 	ctx := &buildContext{}
 
 	t = reflectElem(t)
 
+	if err := mapTags.checkRules(); err != nil {
+		return nil, err
+	}
+
 	// parse struct option if available
 	tag, err := parseStructTagStructOption(ctx, t, level{}, mapTags, &options)
 	if err != nil {
@@ -21,6 +25,9 @@ func buildStructInfo[IT any, DC DecodeContext](t reflect.Type, mapTags MapTags,
 		typ: t,
 		tag: tag,
 	}, level{}, mapTags, options)
+	if err == nil {
+		err = ctx.err()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -31,15 +38,20 @@ func buildStructInfo[IT any, DC DecodeContext](t reflect.Type, mapTags MapTags,
 		if err != nil {
 			return nil, err
 		}
+		err = mapTags.checkUnusedRules(ctx.GetUsedValues(mapTagRuleValueKey))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return si, err
 }
 
 // buildStructInfoItem builds a structInfo for the fields of the passed struct.
+// Field errors are added to the context, so all of them can be returned at once.
 // This function is used both to create a new structInfo and override one with new MapTags. In the former case,
 // it returns copies of the fields and don't change the original.
-func buildStructInfoItem[IT any, DC DecodeContext](ctx *buildContext, si *structInfo, lvl level, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error) {
+func buildStructInfoItem(ctx *buildContext, si *structInfo, lvl level, mapTags MapTags, options structInfoOptions) (*structInfo, error) {
 	siBuild := buildCloneStructInfo(ctx, si, false)
 
 	if siBuild.tag != nil && siBuild.tag.IsSO {
@@ -47,6 +59,9 @@ func buildStructInfoItem[IT any, DC DecodeContext](ctx *buildContext, si *struct
 		if siBuild.tag.Operation == OperationIgnore {
 			return nil, fmt.Errorf("cannot ignore struct option for field '%s'", lvl.StringPath())
 		}
+		if err := validateTagOptions(siBuild.tag, &options); err != nil {
+			return nil, fmt.Errorf("error on struct option for field '%s': %w", lvl.StringPath(), err)
+		}
 
 		// if not recursing, skip checking fields
 		if !siBuild.tag.SORecurse {
@@ -74,24 +89,56 @@ func buildStructInfoItem[IT any, DC DecodeContext](ctx *buildContext, si *struct
 
 		// parse struct tag or equivalent map tag.
 		if tag, err := parseStructTag(ctx, field, curlevel, mapTags, &options); err != nil {
-			return nil, fmt.Errorf("error on field '%s': %w", curlevel.StringPath(), err)
+			ctx.fieldError(fmt.Errorf("error on field '%s': %w", curlevel.StringPath(), err))
+			continue
 		} else if tag != nil {
 			sifield.tag = tag
+			sifield.plan = nil // the resolve function may depend on the tag.
 		}
 
 		if sifield.tag == nil {
-			return nil, fmt.Errorf("field '%s' configuration not found", curlevel.StringPath())
+			ctx.fieldError(fmt.Errorf("field '%s' configuration not found", curlevel.StringPath()))
+			continue
+		}
+		if err := validateTagOptions(sifield.tag, &options); err != nil {
+			ctx.fieldError(fmt.Errorf("error on field '%s': %w", curlevel.StringPath(), err))
+			continue
+		}
+
+		if sifield.plan == nil {
+			sifield.plan = buildFieldPlan(field, sifield.tag, options)
 		}
 
 		if sifield.tag.Operation == OperationRecurse {
 			// recurse into inner struct
 			if !isStruct(field.Type) {
-				return nil, fmt.Errorf("field '%s' must be a struct to use recurse but is '%s'", field.Name, field.Type.String())
+				ctx.fieldError(fmt.Errorf("field '%s' must be a struct to use recurse but is '%s'", field.Name, field.Type.String()))
+				continue
 			}
 			var err error
 			sifield, err = buildStructInfoItem(ctx, sifield, lvl.AppendIfTrue(!field.Anonymous, field.Name), mapTags, options)
 			if err != nil {
-				return nil, err
+				ctx.fieldError(err)
+				continue
+			}
+		} else if sifield.tag.Operation == OperationRecurseList {
+			// recurse into the list element struct
+			if field.Type.Kind() != reflect.Slice || !isStruct(field.Type.Elem()) {
+				ctx.fieldError(fmt.Errorf("field '%s' must be a slice of structs to use recurse_list but is '%s'", field.Name, field.Type.String()))
+				continue
+			}
+			elem := sifield.elem
+			if elem == nil {
+				elem = &structInfo{
+					typ:  reflectElem(field.Type.Elem()),
+					path: curlevel.Path(),
+				}
+			}
+			var err error
+			sifield.elem, err = buildStructInfoItem(ctx, elem, curlevel, mapTags, options)
+			if err != nil {
+				ctx.fieldError(err)
+				continue
 			}
 		}
 
@@ -111,9 +158,11 @@ func buildCloneStructInfo(ctx *buildContext, si *structInfo, withFields bool) *s
 		field: si.field,
 		tag:   si.tag,
 		path:  si.path,
+		plan:  si.plan,
 	}
 	if withFields {
 		ret.fields = si.fields
+		ret.elem = si.elem
 	}
 	return ret
 }
@@ -1,17 +1,84 @@
 package instruct
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+
+	"github.com/rrgmc/instruct/types"
 )
 
+// structInfoOptions are the options used to build a structInfo.
+type structInfoOptions struct {
+	TagName         string          // struct tag name.
+	DefaultRequired bool            // whether the default for fields should be "required" or "not required"
+	FieldNameMapper FieldNameMapper // field name mapper.
+	NameFromTags    []string        // other struct tags to get the name from, checked before FieldNameMapper.
+	Resolver        Resolver        // resolver used to build the field decode plan, may be nil.
+	OptionsSpec     tagOptionsSpecs // tag options accepted by the operations, may be nil.
+}
+
+// tagOptionsSpecs returns the tag options accepted by an operation, or false if the operation doesn't declare
+// them.
+type tagOptionsSpecs interface {
+	optionsSpec(operation string) ([]types.TagOptionSpec, bool)
+}
+
+// validateTagOptions checks the tag options against the ones accepted by the operation, if it declares them.
+func validateTagOptions(tag *Tag, options *structInfoOptions) error {
+	if options.OptionsSpec == nil || len(tag.Options.options) == 0 {
+		return nil
+	}
+	specs, ok := options.OptionsSpec.optionsSpec(tag.Operation)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(tag.Options.options))
+	for name := range tag.Options.options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec, found := findTagOptionSpec(specs, name)
+		if !found {
+			return fmt.Errorf("unknown option '%s' for operation '%s'", name, tag.Operation)
+		}
+		if err := spec.Validate(tag.Options.options[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findTagOptionSpec(specs []types.TagOptionSpec, name string) (types.TagOptionSpec, bool) {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return types.TagOptionSpec{}, false
+}
+
 type buildContext struct {
 	usedValues       map[string]map[string]bool
 	clone            bool
 	skipMapTags      bool
 	skipStructField  bool
 	skipStructOption bool
+	errs             []error // field errors.
+}
+
+// fieldError adds a field error. The field is skipped, and the build continues to find the errors of the other
+// fields.
+func (d *buildContext) fieldError(err error) {
+	d.errs = append(d.errs, err)
+}
+
+// err returns all the field errors joined, or nil if there are none.
+func (d *buildContext) err() error {
+	return errors.Join(d.errs...)
 }
 
 func (d *buildContext) ValueUsed(operation string, name string) {
@@ -37,8 +104,8 @@ func (d *buildContext) GetUsedValues(operation string) map[string]bool {
 }
 
 // structInfoFindOptionsFieldStructField finds a struct option field inside the struct fields.
-func structInfoFindOptionsFieldStructField[IT any, DC DecodeContext](ctx *buildContext, t reflect.Type, lvl level,
-	mapTags MapTags, options *DefaultOptions[IT, DC]) (*Tag, error) {
+func structInfoFindOptionsFieldStructField(ctx *buildContext, t reflect.Type, lvl level,
+	mapTags MapTags, options *structInfoOptions) (*Tag, error) {
 	var tag *Tag
 
 	for i := 0; i < t.NumField(); i++ {
@@ -59,8 +126,8 @@ func structInfoFindOptionsFieldStructField[IT any, DC DecodeContext](ctx *buildC
 }
 
 // structInfoFindOptionsFieldMapTags finds a struct option field from the MapTags.
-func structInfoFindOptionsFieldMapTags[IT any, DC DecodeContext](ctx *buildContext, t reflect.Type, lvl level,
-	mapTags MapTags, options *DefaultOptions[IT, DC]) (*Tag, error) {
+func structInfoFindOptionsFieldMapTags(ctx *buildContext, t reflect.Type, lvl level,
+	mapTags MapTags, options *structInfoOptions) (*Tag, error) {
 	if mapTags != nil {
 		if _, ok := mapTags.findStringPath(lvl.Append(StructOptionMapTag).Path()); ok {
 			return parseStructTag(ctx, reflect.StructField{Name: StructOptionMapTag},
@@ -71,27 +138,27 @@ func structInfoFindOptionsFieldMapTags[IT any, DC DecodeContext](ctx *buildConte
 }
 
 // structInfoProvider abstracts a posssible cache of structInfo
-type structInfoProvider[IT any, DC DecodeContext] interface {
-	provide(t reflect.Type, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error)
+type structInfoProvider interface {
+	provide(t reflect.Type, mapTags MapTags, options structInfoOptions) (*structInfo, error)
 	remove(t reflect.Type)
 }
 
 // defaultStructInfoProvider is a structInfoProvider that never caches.
-type defaultStructInfoProvider[IT any, DC DecodeContext] struct {
+type defaultStructInfoProvider struct {
 }
 
-func (d defaultStructInfoProvider[IT, DC]) provide(t reflect.Type, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error) {
+func (d defaultStructInfoProvider) provide(t reflect.Type, mapTags MapTags, options structInfoOptions) (*structInfo, error) {
 	return buildStructInfo(t, mapTags, options)
 }
 
-func (d defaultStructInfoProvider[IT, DC]) remove(t reflect.Type) {}
+func (d defaultStructInfoProvider) remove(t reflect.Type) {}
 
 // cachedStructInfoProvider is a structInfoProvider that always caches.
-type cachedStructInfoProvider[IT any, DC DecodeContext] struct {
+type cachedStructInfoProvider struct {
 	cache sync.Map
 }
 
-func (d *cachedStructInfoProvider[IT, DC]) provide(t reflect.Type, mapTags MapTags, options DefaultOptions[IT, DC]) (*structInfo, error) {
+func (d *cachedStructInfoProvider) provide(t reflect.Type, mapTags MapTags, options structInfoOptions) (*structInfo, error) {
 	csi, ok := d.cache.Load(t)
 	if ok {
 		return csi.(*structInfo), nil
@@ -107,6 +174,6 @@ func (d *cachedStructInfoProvider[IT, DC]) provide(t reflect.Type, mapTags MapTa
 	return si, nil
 }
 
-func (d *cachedStructInfoProvider[IT, DC]) remove(t reflect.Type) {
+func (d *cachedStructInfoProvider) remove(t reflect.Type) {
 	d.cache.Delete(t)
 }
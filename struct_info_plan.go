@@ -0,0 +1,56 @@
+package instruct
+
+import (
+	"reflect"
+
+	"github.com/rrgmc/instruct/resolver"
+)
+
+// fieldPlan is the precompiled decode plan of a struct field, built once per type so the decode loop doesn't
+// need to inspect the field type or the Resolver on every call.
+type fieldPlan struct {
+	index   int                  // index of the field in its struct, for [reflect.Value.Field].
+	isList  bool                 // whether the field is a list (slices, arrays and maps of primitive types)
+	resolve resolver.ResolveFunc // resolve function for the field type, nil if there is no Resolver
+}
+
+// buildFieldPlan builds the decode plan for a struct field with its tag.
+func buildFieldPlan(field reflect.StructField, tag *Tag, options structInfoOptions) *fieldPlan {
+	typ := field.Type
+	// only check slices/arrays/maps for primitive types, otherwise "type UUID [16]byte" would be checked as an array
+	isPrimitive := typ.PkgPath() == ""
+	return &fieldPlan{
+		index: field.Index[len(field.Index)-1],
+		isList: isPrimitive && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array ||
+			typ.Kind() == reflect.Map),
+		resolve: fieldResolveFunc(options.Resolver, typ, tag),
+	}
+}
+
+// fieldResolveFunc selects the resolve function of the Resolver for a field type and tag, or nil if there is no
+// Resolver.
+func fieldResolveFunc(r Resolver, typ reflect.Type, tag *Tag) resolver.ResolveFunc {
+	switch r := r.(type) {
+	case nil:
+		return nil
+	case TagResolverFunc:
+		return r.TagResolveFunc(typ, tag)
+	case TagResolver:
+		return func(target reflect.Value, value any, options resolver.Options) error {
+			return r.ResolveTag(target, value, tag)
+		}
+	case ResolverFunc:
+		return r.TypeResolveFunc(typ)
+	case ResolverWithOptions:
+		return r.ResolveOptions
+	default:
+		return func(target reflect.Value, value any, options resolver.Options) error {
+			return r.Resolve(target, value)
+		}
+	}
+}
+
+// fieldValue returns the field value from its struct value.
+func (p *fieldPlan) fieldValue(structValue reflect.Value) reflect.Value {
+	return structValue.Field(p.index)
+}
@@ -1,11 +1,11 @@
 package instruct
 
 import (
-	"net/http"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/rrgmc/instruct/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,7 +40,7 @@ func TestStructInfo(t *testing.T) {
 		name    string
 		typ     reflect.Type
 		mapTags MapTags
-		options DefaultOptions[*http.Request, TestDecodeContext]
+		options structInfoOptions
 		wantErr bool
 	}{
 		{
@@ -105,7 +105,7 @@ func TestStructInfoModify(t *testing.T) {
 		TestData2
 	}
 
-	options := DefaultOptions[*http.Request, TestDecodeContext]{
+	options := structInfoOptions{
 		FieldNameMapper: DefaultFieldNameMapper,
 		TagName:         "inreq",
 	}
@@ -136,3 +136,84 @@ func TestStructInfoModify(t *testing.T) {
 	require.Equal(t, "query", si.fieldByName("TestData2").fieldByName("X").tag.Operation)
 	require.Equal(t, "query", si.fieldByName("TestData2").fieldByName("Y").tag.Operation)
 }
+
+func TestStructInfoOptionsSpec(t *testing.T) {
+	type Inner struct {
+		A string `instruct:"query,explodsep=;"`
+	}
+
+	tests := []struct {
+		name    string
+		data    any
+		mapTags MapTags
+		err     string
+	}{
+		{
+			name: "valid",
+			data: &struct {
+				A []string          `instruct:"query,explode=false,explodesep=;,desc=Values"`
+				B map[string]string `instruct:"query,mapsep=;,mapkvsep=:"`
+				C string            `instruct:"header,anything=1"`
+			}{},
+		},
+		{
+			name: "unknown option",
+			data: &struct {
+				Inner Inner `instruct:"recurse"`
+			}{},
+			err: "error on field 'Inner.A': unknown option 'explodsep' for operation 'query'",
+		},
+		{
+			name: "malformed option",
+			data: &struct {
+				A []string `instruct:"query,explode=yes"`
+			}{},
+			err: "error on field 'A': invalid boolean value 'yes' for option 'explode'",
+		},
+		{
+			name: "map tags",
+			data: &struct {
+				A string
+			}{},
+			mapTags: MapTags{
+				"A": "query,mode=x",
+			},
+			err: "error on field 'A': unknown option 'mode' for operation 'query'",
+		},
+		{
+			name: "struct option",
+			data: &struct {
+				_ StructOption `instruct:"query,explode=1"`
+			}{},
+		},
+		{
+			name: "invalid struct option",
+			data: &struct {
+				_ StructOption `instruct:"query,explode=2"`
+			}{},
+			err: "error on struct option for field '': invalid boolean value '2' for option 'explode'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opt := GetTestDecoderOptions()
+			_, err := buildStructInfo(reflect.TypeOf(test.data), test.mapTags, opt.structInfoOptions())
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestTagOptionSpecValidate(t *testing.T) {
+	spec := types.TagOptionSpec{Name: "mode", Type: types.TagOptionEnum, Values: []string{"a", "b"}}
+	require.NoError(t, spec.Validate("a"))
+	require.EqualError(t, spec.Validate("c"), "invalid value 'c' for option 'mode', must be one of: a, b")
+
+	spec = types.TagOptionSpec{Name: "size", Type: types.TagOptionInt}
+	require.NoError(t, spec.Validate("-10"))
+	require.EqualError(t, spec.Validate("x"), "invalid integer value 'x' for option 'size'")
+}
@@ -4,21 +4,38 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // Tag contains the options parsed from the struct tags or MapTags
 type Tag struct {
-	Operation string     // decode operation
-	Name      string     // data name (for example, header or query param name)
-	Required  bool       // whether this field is required to be set
-	Options   TagOptions // options
-	IsSO      bool
-	SOWhen    string // struct options: when to parse (before or after the fields)
-	SORecurse bool   // struct options: whether to recurse into inner struct
+	Operation  string     // decode operation
+	Name       string     // data name (for example, header or query param name)
+	Required   bool       // whether this field is required to be set
+	Default    string     // default value to be used if the value was not found
+	HasDefault bool       // whether a default value was set
+	Options    TagOptions // options
+	IsSO       bool
+	SOWhen     string // struct options: when to parse (before or after the fields)
+	SORecurse  bool   // struct options: whether to recurse into inner struct
+
+	mapTagsRule string    // description of the MapTagsRule that produced the tag, if any.
+	source      tagSource // which options were present in the parsed text.
 }
 
+// tagSource records which of the options with a default were present in the parsed tag text, so Tag.String
+// writes only them.
+type tagSource uint8
+
+const (
+	tagSourceParsed tagSource = 1 << iota // the tag was parsed from text.
+	tagSourceName
+	tagSourceRequired
+	tagSourceSORecurse
+)
+
 type TagOptions struct {
 	options map[string]string
 }
@@ -57,12 +74,24 @@ func (t *TagOptions) BoolValue(name string, defaultValue bool) (bool, error) {
 	return defaultValue, nil
 }
 
+// defaultValue returns the default value to be sent to the Resolver. For lists, the value is split using
+// defaultListSeparator.
+func (t *Tag) defaultValue(isList bool) any {
+	if isList {
+		if t.Default == "" {
+			return []string{}
+		}
+		return strings.Split(t.Default, defaultListSeparator)
+	}
+	return t.Default
+}
+
 // parseStructTagStructField parses a Tag from a struct tag
-func parseStructTagStructField[IT any, DC DecodeContext](ctx *buildContext, field reflect.StructField, level level,
-	options *DefaultOptions[IT, DC]) (*Tag, error) {
+func parseStructTagStructField(ctx *buildContext, field reflect.StructField, level level,
+	options *structInfoOptions) (*Tag, error) {
 	tags, ok := field.Tag.Lookup(options.TagName)
 	if ok {
-		return parseTags(field.Name, tags, options)
+		return parseFieldTags(field, tags, options)
 	}
 
 	if field.Anonymous && isStruct(field.Type) {
@@ -77,8 +106,8 @@ func parseStructTagStructField[IT any, DC DecodeContext](ctx *buildContext, fiel
 }
 
 // parseStructTagStructField parses a Tag from MapTags
-func parseStructTagMapTags[IT any, DC DecodeContext](ctx *buildContext, field reflect.StructField, level level, mapTags MapTags,
-	options *DefaultOptions[IT, DC]) (*Tag, error) {
+func parseStructTagMapTags(ctx *buildContext, field reflect.StructField, level level, mapTags MapTags,
+	options *structInfoOptions) (*Tag, error) {
 	if mapTags == nil {
 		return nil, nil
 	}
@@ -88,9 +117,16 @@ func parseStructTagMapTags[IT any, DC DecodeContext](ctx *buildContext, field re
 
 		switch xft := ft.(type) {
 		case string:
-			return parseTags(field.Name, xft, options)
+			return parseFieldTags(field, xft, options)
 		case MapTags, map[string]any:
 			if isStruct(field.Type) {
+				// a struct option in the inner MapTags takes precedence over recursing.
+				if tag, err := structInfoFindOptionsFieldMapTags(ctx, field.Type, level, mapTags, options); err != nil {
+					return nil, err
+				} else if tag != nil {
+					tag.IsSO = true
+					return tag, nil
+				}
 				return &Tag{
 					Operation: OperationRecurse,
 					Required:  options.DefaultRequired,
@@ -102,12 +138,31 @@ func parseStructTagMapTags[IT any, DC DecodeContext](ctx *buildContext, field re
 		return nil, fmt.Errorf("unknown map tags item type (only 'string', 'defaultMapTags' and 'map[string]any' are allowed): %T", ft)
 	}
 
+	if field.Type == nil || isOptionField(field) {
+		return nil, nil
+	}
+
+	// exact keys have priority over rules.
+	rule, ruleIndex, ok, err := mapTags.findRule(level.Path(), field.Type)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		ctx.ValueUsed(mapTagRuleValueKey, strconv.Itoa(ruleIndex))
+		tag, err := parseFieldTags(field, rule.Tag, options)
+		if err != nil {
+			return nil, fmt.Errorf("map tags rule %d (%s): %w", ruleIndex, rule, err)
+		}
+		tag.mapTagsRule = fmt.Sprintf("%d (%s)", ruleIndex, rule)
+		return tag, nil
+	}
+
 	return nil, nil
 }
 
 // parseStructTagStructOption finds a struct option field from either the MapTags or the struct fields.
-func parseStructTagStructOption[IT any, DC DecodeContext](ctx *buildContext, t reflect.Type, lvl level,
-	mapTags MapTags, options *DefaultOptions[IT, DC]) (*Tag, error) {
+func parseStructTagStructOption(ctx *buildContext, t reflect.Type, lvl level,
+	mapTags MapTags, options *structInfoOptions) (*Tag, error) {
 	if !ctx.skipMapTags {
 		tag, err := structInfoFindOptionsFieldMapTags(ctx, t, lvl, mapTags, options)
 		if err != nil {
@@ -134,9 +189,9 @@ func parseStructTagStructOption[IT any, DC DecodeContext](ctx *buildContext, t r
 }
 
 // parseStructTagStructField parses a Tag from a MapTags or a struct tag.
-func parseStructTag[IT any, DC DecodeContext](ctx *buildContext, field reflect.StructField,
+func parseStructTag(ctx *buildContext, field reflect.StructField,
 	lvl level, mapTags MapTags,
-	options *DefaultOptions[IT, DC]) (*Tag, error) {
+	options *structInfoOptions) (*Tag, error) {
 	if !ctx.skipMapTags {
 		tag, err := parseStructTagMapTags(ctx, field, lvl, mapTags, options)
 		if err != nil {
@@ -169,58 +224,156 @@ func parseStructTag[IT any, DC DecodeContext](ctx *buildContext, field reflect.S
 }
 
 // parseTags parses a Tag from a textual description, in the form "operation,field1=value1,field2=value2".
-func parseTags[IT any, DC DecodeContext](fieldName string, tagValue string, options *DefaultOptions[IT, DC]) (*Tag, error) {
+// Values can be single-quoted to contain commas, like "default='a,b'", and the characters ",", "=", "'" and "\\"
+// can be escaped using a backslash. Inside quotes, only "'" and "\\" need escaping.
+func parseTags(fieldName string, tagValue string, options *structInfoOptions) (*Tag, error) {
+	return parseTagsWithName(fieldName, "", tagValue, options)
+}
+
+// parseFieldTags parses a Tag for a struct field. If the "name" option is not set, the name is taken from the
+// first of the NameFromTags struct tags that has one, before using the FieldNameMapper.
+func parseFieldTags(field reflect.StructField, tagValue string, options *structInfoOptions) (*Tag, error) {
+	return parseTagsWithName(field.Name, nameFromStructTags(field.Tag, options.NameFromTags), tagValue, options)
+}
+
+// parseTagsWithName parses a Tag, using defaultName if the "name" option is not set. If defaultName is blank,
+// the FieldNameMapper is used.
+func parseTagsWithName(fieldName string, defaultName string, tagValue string, options *structInfoOptions) (*Tag, error) {
 	ret := &Tag{
 		Name:     "",
 		Required: options.DefaultRequired,
 		Options:  NewTagOptions(),
+		source:   tagSourceParsed,
 	}
 
-	s := tagValue
-	for s != "" {
-		var value string
-		value, s, _ = strings.Cut(s, ",")
-		if ret.Operation == "" {
-			if value == "" {
-				return nil, errors.New("operation cannot be blank")
-			}
-			ret.Operation = value
-		} else if value != "" {
-			oname, oval, ofound := strings.Cut(value, "=")
-			if !ofound {
-				return nil, fmt.Errorf("unnamed tag option: %s", value)
+	operation, tagOptions := splitTag(tagValue)
+	if tagValue != "" {
+		if operation == "" {
+			return nil, errors.New("operation cannot be blank")
+		}
+		ret.Operation = operation
+	}
+
+	for _, option := range tagOptions {
+		if !option.hasValue {
+			return nil, fmt.Errorf("unnamed tag option: %s", option.raw)
+		}
+		oname, oval := option.name, option.value
+		if oname == "name" {
+			ret.Name = oval
+			ret.source |= tagSourceName
+		} else if oname == "default" {
+			ret.Default = oval
+			ret.HasDefault = true
+		} else if oname == "required" {
+			b, err := strconv.ParseBool(oval)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing 'required' boolean option: %w", err)
 			}
-			if oname == "name" {
-				ret.Name = oval
-			} else if oname == "required" {
+			ret.Required = b
+			ret.source |= tagSourceRequired
+		} else if strings.HasPrefix(oname, "so_") {
+			if oname == "so_when" {
+				if oval != SOOptionWhenBefore && oval != SOOptionWhenAfter {
+					return nil, fmt.Errorf("invalid 'when' option value: %s", oval)
+				}
+				ret.SOWhen = oval
+			} else if oname == "so_recurse" {
 				b, err := strconv.ParseBool(oval)
 				if err != nil {
-					return nil, fmt.Errorf("error parsing 'required' boolean option: %w", err)
-				}
-				ret.Required = b
-			} else if strings.HasPrefix(oname, "so_") {
-				if oname == "so_when" {
-					if oval != SOOptionWhenBefore && oval != SOOptionWhenAfter {
-						return nil, fmt.Errorf("invalid 'when' option value: %s", oval)
-					}
-					ret.SOWhen = oval
-				} else if oname == "so_recurse" {
-					b, err := strconv.ParseBool(oval)
-					if err != nil {
-						return nil, fmt.Errorf("error parsing 'so_recurse' boolean option: %w", err)
-					}
-					ret.SORecurse = b
-				} else {
-					return nil, fmt.Errorf("unknown struct option name: %s", oname)
+					return nil, fmt.Errorf("error parsing 'so_recurse' boolean option: %w", err)
 				}
+				ret.SORecurse = b
+				ret.source |= tagSourceSORecurse
 			} else {
-				ret.Options.options[oname] = oval
+				return nil, fmt.Errorf("unknown struct option name: %s", oname)
 			}
+		} else {
+			ret.Options.options[oname] = oval
 		}
 	}
 
+	if ret.Name == "" {
+		ret.Name = defaultName
+	}
 	if ret.Name == "" {
 		ret.Name = options.FieldNameMapper(ret.Operation, fieldName)
 	}
 	return ret, nil
 }
+
+// nameFromStructTags returns the name from the first struct tag that has one, like `json:"user_id,omitempty"`.
+// Flags after the first comma are ignored, and "-" is skipped.
+func nameFromStructTags(structTag reflect.StructTag, tagNames []string) string {
+	for _, tagName := range tagNames {
+		value, ok := structTag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(value, ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// ParseTag parses a struct tag value in the "operation,option1=value1,option2=value2" format, the same way the
+// Decoder does. If the "name" option is not set, it is built from fieldName using fieldNameMapper.
+func ParseTag(fieldName string, tagValue string, defaultRequired bool, fieldNameMapper FieldNameMapper) (*Tag, error) {
+	return parseTags(fieldName, tagValue, &structInfoOptions{
+		DefaultRequired: defaultRequired,
+		FieldNameMapper: fieldNameMapper,
+	})
+}
+
+// MustParseTag is like ParseTag but panics on error. It is used by generated code.
+func MustParseTag(fieldName string, tagValue string, defaultRequired bool, fieldNameMapper FieldNameMapper) *Tag {
+	tag, err := ParseTag(fieldName, tagValue, defaultRequired, fieldNameMapper)
+	if err != nil {
+		panic(err)
+	}
+	return tag
+}
+
+// String returns the tag in the canonical tag text format, which parses back to an equal Tag for the same field.
+// The operation is followed by the name, required, default and struct options, and the other options sorted by
+// name. Only the options present in the parsed text are written, so names from the FieldNameMapper or
+// NameFromTags are not. Tags not created by parsing always write the name and required options.
+// Values containing special characters are quoted.
+func (t *Tag) String() string {
+	var b strings.Builder
+	b.WriteString(escapeTagText(t.Operation, false))
+	writeOption := func(name, value string) {
+		b.WriteString(",")
+		b.WriteString(escapeTagText(name, true))
+		b.WriteString("=")
+		b.WriteString(quoteTagValue(value))
+	}
+	// tags not parsed from text write the name and required options, as they can't be derived from the field.
+	parsed := t.source&tagSourceParsed != 0
+	if !parsed || t.source&tagSourceName != 0 {
+		writeOption("name", t.Name)
+	}
+	if !parsed || t.source&tagSourceRequired != 0 {
+		writeOption("required", strconv.FormatBool(t.Required))
+	}
+	if t.HasDefault {
+		writeOption("default", t.Default)
+	}
+	if t.SOWhen != "" {
+		writeOption("so_when", t.SOWhen)
+	}
+	if t.SORecurse || t.source&tagSourceSORecurse != 0 {
+		writeOption("so_recurse", strconv.FormatBool(t.SORecurse))
+	}
+	names := make([]string, 0, len(t.Options.options))
+	for name := range t.Options.options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeOption(name, t.Options.options[name])
+	}
+	return b.String()
+}
@@ -0,0 +1,320 @@
+package instruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rrgmc/instruct/coerce"
+	"github.com/rrgmc/instruct/resolver"
+	"github.com/rrgmc/instruct/types"
+)
+
+// Tag options used by TagFormatResolver.
+const (
+	// TagOptionTimeLayout are the time.Time layouts tried in order, separated by "|", like "2006-01-02|RFC3339".
+	// The names of the time package layout constants can be used. Default time.RFC3339.
+	TagOptionTimeLayout = "layout"
+	// TagOptionTimeLocation is the time.Time location for layouts without a zone, and for epoch values, like
+	// "America/Sao_Paulo". Default UTC.
+	TagOptionTimeLocation = "tz"
+	// TagOptionTimeEpoch is the unit of time.Time Unix epoch values, "s", "ms", "us" or "ns". Integers and
+	// numeric strings which don't match any layout are parsed as epoch values. Default is to not accept them.
+	TagOptionTimeEpoch = "epoch"
+	// TagOptionIntBase is the integer base, from 2 to 36, or 0 to use the "0x", "0o" and "0b" prefixes.
+	// Default 10.
+	TagOptionIntBase = "base"
+)
+
+// TimeLayoutSeparator separates the layouts of the TagOptionTimeLayout option.
+const TimeLayoutSeparator = "|"
+
+// timeLayoutNames are the layout constants of the time package which can be used by name.
+var timeLayoutNames = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// timeEpochNames are the values of the TagOptionTimeEpoch option.
+var timeEpochNames = map[string]coerce.TimeEpoch{
+	"s":  coerce.TimeEpochSeconds,
+	"ms": coerce.TimeEpochMilliseconds,
+	"us": coerce.TimeEpochMicroseconds,
+	"ns": coerce.TimeEpochNanoseconds,
+}
+
+// TagFormatResolver is a TagResolver that resolves time.Time fields using the "layout", "tz" and "epoch" tag
+// options, and integer fields, including named integer types, using the "base" tag option. Pointers and slices of these types are also
+// supported. Fields without these options are resolved by the wrapped Resolver, unless default time options were
+// set with WithTimeOptions, in which case they are used for all time.Time fields.
+//
+//	type Request struct {
+//		Date    time.Time `instruct:"query,layout=2006-01-02,tz=America/Sao_Paulo"`
+//		Created time.Time `instruct:"query,layout=RFC3339|DateOnly,epoch=ms"`
+//		Color   uint32    `instruct:"query,base=16"`
+//	}
+type TagFormatResolver struct {
+	resolver    Resolver
+	timeOptions *coerce.TimeOptions
+	locations   sync.Map // map[string]*time.Location
+}
+
+// NewTagFormatResolver creates a TagFormatResolver wrapping a Resolver.
+func NewTagFormatResolver(resolver Resolver, options ...TagFormatResolverOption) *TagFormatResolver {
+	ret := &TagFormatResolver{
+		resolver: resolver,
+	}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+type TagFormatResolverOption func(*TagFormatResolver)
+
+// WithTimeOptions sets the default time options, used for all time.Time fields. The tag options override them.
+func WithTimeOptions(options coerce.TimeOptions) TagFormatResolverOption {
+	return func(r *TagFormatResolver) {
+		r.timeOptions = &options
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Resolve resolves the value using the wrapped Resolver.
+func (r *TagFormatResolver) Resolve(target reflect.Value, value any) error {
+	return r.resolver.Resolve(target, value)
+}
+
+// ResolveTag resolves the value using the tag format options, or using the wrapped Resolver if the field type
+// doesn't use them.
+func (r *TagFormatResolver) ResolveTag(target reflect.Value, value any, tag *Tag) error {
+	return r.TagResolveFunc(target.Type(), tag)(target, value, &tag.Options)
+}
+
+// TagResolveFunc returns the function to resolve the type using the tag format options, which are parsed only
+// once, or the resolve function of the wrapped Resolver if the field type doesn't use them.
+func (r *TagFormatResolver) TagResolveFunc(typ reflect.Type, tag *Tag) resolver.ResolveFunc {
+	resolve := r.formatResolveFunc(typ, &tag.Options)
+	if resolve == nil {
+		return fieldResolveFunc(r.resolver, typ, tag)
+	}
+	return func(target reflect.Value, value any, options resolver.Options) error {
+		if err := resolve(target, value); err != nil {
+			return types.NewCoerceError(err)
+		}
+		return nil
+	}
+}
+
+// OptionsSpec returns the tag options accepted by the resolver, including the ones of the wrapped Resolver.
+func (r *TagFormatResolver) OptionsSpec() []types.TagOptionSpec {
+	ret := []types.TagOptionSpec{
+		{
+			Name:        TagOptionTimeLayout,
+			Type:        types.TagOptionString,
+			Default:     "RFC3339",
+			Description: "time layouts, separated by '|'",
+		},
+		{
+			Name:        TagOptionTimeLocation,
+			Type:        types.TagOptionString,
+			Default:     "UTC",
+			Description: "time location for layouts without a zone",
+		},
+		{
+			Name:        TagOptionTimeEpoch,
+			Type:        types.TagOptionEnum,
+			Values:      []string{"s", "ms", "us", "ns"},
+			Description: "unit of Unix epoch time values",
+		},
+		{
+			Name:        TagOptionIntBase,
+			Type:        types.TagOptionInt,
+			Default:     "10",
+			Description: "integer base",
+		},
+	}
+	if ros, ok := r.resolver.(DecodeOperationOptionsSpec); ok {
+		ret = append(ret, ros.OptionsSpec()...)
+	}
+	return ret
+}
+
+// formatResolveFunc returns the function to resolve the type using the tag options, or nil if the type or its
+// options are not handled.
+func (r *TagFormatResolver) formatResolveFunc(typ reflect.Type, options *TagOptions) func(target reflect.Value, value any) error {
+	switch {
+	case typ == timeType:
+		if r.timeOptions == nil && !options.Exists(TagOptionTimeLayout) && !options.Exists(TagOptionTimeLocation) &&
+			!options.Exists(TagOptionTimeEpoch) {
+			return nil
+		}
+		// option errors are returned when resolving, like value errors.
+		timeOptions, err := r.fieldTimeOptions(options)
+		return func(target reflect.Value, value any) error {
+			if err != nil {
+				return err
+			}
+			return resolveTime(target, value, timeOptions)
+		}
+	case isIntKind(typ.Kind()):
+		// named integer types like "type Color uint32" are also set using the base.
+		if !options.Exists(TagOptionIntBase) {
+			return nil
+		}
+		base, err := intBaseOption(options)
+		return func(target reflect.Value, value any) error {
+			if err != nil {
+				return err
+			}
+			return resolveIntBase(target, value, base)
+		}
+	case typ.Kind() == reflect.Pointer:
+		elemResolve := r.formatResolveFunc(typ.Elem(), options)
+		if elemResolve == nil {
+			return nil
+		}
+		return func(target reflect.Value, value any) error {
+			ptrValue := reflect.New(typ.Elem())
+			if err := elemResolve(ptrValue.Elem(), value); err != nil {
+				return err
+			}
+			target.Set(ptrValue)
+			return nil
+		}
+	case typ.PkgPath() == "" && typ.Kind() == reflect.Slice:
+		elemResolve := r.formatResolveFunc(typ.Elem(), options)
+		if elemResolve == nil {
+			return nil
+		}
+		return func(target reflect.Value, value any) error {
+			sourceValue := reflect.ValueOf(value)
+			if sourceValue.Kind() != reflect.Slice && sourceValue.Kind() != reflect.Array {
+				return fmt.Errorf("expected an array to coerce an array into")
+			}
+			targetSliceValue := reflect.MakeSlice(typ, sourceValue.Len(), sourceValue.Len())
+			for i := 0; i < sourceValue.Len(); i++ {
+				if err := elemResolve(targetSliceValue.Index(i), sourceValue.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			target.Set(targetSliceValue)
+			return nil
+		}
+	}
+	return nil
+}
+
+// resolveTime resolves a time.Time using the field time options.
+func resolveTime(target reflect.Value, value any, timeOptions coerce.TimeOptions) error {
+	t, err := coerce.TimeWithOptions(value, timeOptions)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// fieldTimeOptions returns the default time options overridden by the tag options.
+func (r *TagFormatResolver) fieldTimeOptions(options *TagOptions) (coerce.TimeOptions, error) {
+	var ret coerce.TimeOptions
+	if r.timeOptions != nil {
+		ret = *r.timeOptions
+	}
+	if layouts, ok := options.Get(TagOptionTimeLayout); ok {
+		ret.Layouts = nil
+		for _, layout := range strings.Split(layouts, TimeLayoutSeparator) {
+			if named, ok := timeLayoutNames[layout]; ok {
+				layout = named
+			}
+			ret.Layouts = append(ret.Layouts, layout)
+		}
+	} else if len(ret.Layouts) == 0 {
+		ret.Layouts = []string{time.RFC3339}
+	}
+	if name, ok := options.Get(TagOptionTimeLocation); ok {
+		loc, err := r.location(name)
+		if err != nil {
+			return coerce.TimeOptions{}, err
+		}
+		ret.Location = loc
+	}
+	if name, ok := options.Get(TagOptionTimeEpoch); ok {
+		epoch, ok := timeEpochNames[name]
+		if !ok {
+			return coerce.TimeOptions{}, fmt.Errorf("invalid '%s' option '%s'", TagOptionTimeEpoch, name)
+		}
+		ret.Epoch = epoch
+	}
+	return ret, nil
+}
+
+// location returns a cached time.Location.
+func (r *TagFormatResolver) location(name string) (*time.Location, error) {
+	if loc, ok := r.locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' option: %w", TagOptionTimeLocation, err)
+	}
+	r.locations.Store(name, loc)
+	return loc, nil
+}
+
+// intBaseOption parses the base option.
+func intBaseOption(options *TagOptions) (int, error) {
+	base, err := strconv.Atoi(options.Value(TagOptionIntBase, "10"))
+	if err != nil || base == 1 || base < 0 || base > 36 {
+		return 0, fmt.Errorf("invalid '%s' option '%s'", TagOptionIntBase, options.Value(TagOptionIntBase, ""))
+	}
+	return base, nil
+}
+
+// resolveIntBase resolves a signed or unsigned integer using a base.
+func resolveIntBase(target reflect.Value, value any, base int) error {
+	s, err := coerce.String(value)
+	if err != nil {
+		return err
+	}
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, base, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w; %w", types.ErrCoerceInvalid, err)
+		}
+		target.SetInt(v)
+	default:
+		v, err := strconv.ParseUint(s, base, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w; %w", types.ErrCoerceInvalid, err)
+		}
+		target.SetUint(v)
+	}
+	return nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
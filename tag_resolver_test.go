@@ -0,0 +1,224 @@
+package instruct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rrgmc/instruct/coerce"
+	"github.com/rrgmc/instruct/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testColor uint32
+
+type testOffset int16
+
+func TestTagFormatResolver(t *testing.T) {
+	type DataType struct {
+		Date  time.Time   `instruct:"query,layout=2006-01-02"`
+		Local time.Time   `instruct:"query,layout='2006-01-02 15:04',tz=America/Sao_Paulo"`
+		PDate *time.Time  `instruct:"query,layout=02/01/2006"`
+		Dates []time.Time `instruct:"query,layout=2006-01-02"`
+		Color uint32      `instruct:"query,base=16"`
+		Bin   int8        `instruct:"query,base=2"`
+		Auto  []int       `instruct:"query,base=0"`
+		Plain int         `instruct:"query"`
+		Named testColor   `instruct:"query,base=16"`
+		NOff  testOffset  `instruct:"query,base=8"`
+		NPtr  *testColor  `instruct:"query,base=16"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.Resolver = NewTagFormatResolver(opt.Resolver)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	q := url.Values{}
+	q.Set("date", "2024-02-03")
+	q.Set("local", "2024-02-03 10:20")
+	q.Set("pdate", "03/02/2024")
+	q.Set("dates", "2024-01-01,2024-12-31")
+	q.Set("color", "ff8000")
+	q.Set("bin", "-101")
+	q.Set("auto", "0x1f,0b11,0o17,9")
+	q.Set("plain", "12")
+	q.Set("named", "ff")
+	q.Set("noff", "-17")
+	q.Set("nptr", "10")
+	r := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+
+	var data DataType
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	require.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), data.Date)
+	require.Equal(t, time.Date(2024, 2, 3, 10, 20, 0, 0, loc), data.Local)
+	require.NotNil(t, data.PDate)
+	require.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), *data.PDate)
+	require.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}, data.Dates)
+	require.Equal(t, uint32(0xff8000), data.Color)
+	require.Equal(t, int8(-5), data.Bin)
+	require.Equal(t, []int{31, 3, 15, 9}, data.Auto)
+	require.Equal(t, 12, data.Plain)
+	require.Equal(t, testColor(0xff), data.Named)
+	require.Equal(t, testOffset(-15), data.NOff)
+	require.NotNil(t, data.NPtr)
+	require.Equal(t, testColor(16), *data.NPtr)
+}
+
+func TestTagFormatResolverErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  any
+		query string
+		err   string
+	}{
+		{
+			name: "invalid time",
+			data: &struct {
+				Date time.Time `instruct:"query,layout=2006-01-02"`
+			}{},
+			query: "date=03/02/2024",
+			err:   `parsing time "03/02/2024" as "2006-01-02": cannot parse "03/02/2024" as "2006"`,
+		},
+		{
+			name: "invalid location",
+			data: &struct {
+				Date time.Time `instruct:"query,layout=2006-01-02,tz=Nowhere/City"`
+			}{},
+			query: "date=2024-02-03",
+			err:   "invalid 'tz' option: unknown time zone Nowhere/City",
+		},
+		{
+			name: "overflow",
+			data: &struct {
+				Value uint8 `instruct:"query,base=16"`
+			}{},
+			query: "value=1ff",
+			err:   `strconv.ParseUint: parsing "1ff": value out of range`,
+		},
+		{
+			name: "invalid base",
+			data: &struct {
+				Value int `instruct:"query,base=99"`
+			}{},
+			query: "value=1",
+			err:   "invalid 'base' option '99'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opt := GetTestDecoderOptions()
+			opt.Resolver = NewTagFormatResolver(opt.Resolver)
+			dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+			r := httptest.NewRequest(http.MethodGet, "/?"+test.query, nil)
+			err := dec.Decode(r, test.data, GetTestDecoderDecodeOptions(nil))
+			require.ErrorContains(t, err, test.err)
+			var coerceErr types.CoerceError
+			require.ErrorAs(t, err, &coerceErr)
+		})
+	}
+}
+
+func TestTagFormatResolverOptionsSpec(t *testing.T) {
+	type DataType struct {
+		Date time.Time `instruct:"query,layout=2006-01-02"`
+	}
+
+	// the default resolver doesn't accept the format options.
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+	err := dec.Decode(httptest.NewRequest(http.MethodGet, "/", nil), &DataType{}, GetTestDecoderDecodeOptions(nil))
+	require.ErrorContains(t, err, "unknown option 'layout' for operation 'query'")
+
+	type EpochDataType struct {
+		Date time.Time `instruct:"query,epoch=days"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.Resolver = NewTagFormatResolver(opt.Resolver)
+	dec = NewDecoder[*http.Request, TestDecodeContext](opt)
+	err = dec.Decode(httptest.NewRequest(http.MethodGet, "/", nil), &EpochDataType{}, GetTestDecoderDecodeOptions(nil))
+	require.ErrorContains(t, err, "invalid value 'days' for option 'epoch', must be one of: s, ms, us, ns")
+}
+
+func TestTagFormatResolverTimeOptions(t *testing.T) {
+	type DataType struct {
+		Default time.Time `instruct:"query"`
+		Multi   time.Time `instruct:"query,layout=RFC3339|DateOnly"`
+		Epoch   time.Time `instruct:"query,epoch=s"`
+		EpochMS time.Time `instruct:"query,layout=DateOnly,epoch=ms,tz=America/Sao_Paulo"`
+		Plain   string    `instruct:"query"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.Resolver = NewTagFormatResolver(opt.Resolver, WithTimeOptions(coerce.TimeOptions{
+		Layouts: []string{"02/01/2006"},
+		Epoch:   coerce.TimeEpochMilliseconds,
+	}))
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	q := url.Values{}
+	q.Set("default", "03/02/2024")
+	q.Set("multi", "2024-02-03")
+	q.Set("epoch", "1700000000")
+	q.Set("epochms", "1700000000000")
+	q.Set("plain", "x")
+	r := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+
+	var data DataType
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	require.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), data.Default)
+	require.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), data.Multi)
+	// the default layouts are also used by the fields that don't override them.
+	require.Equal(t, time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), data.Epoch)
+	require.Equal(t, time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC).In(loc), data.EpochMS)
+	require.Equal(t, "x", data.Plain)
+
+	q.Set("multi", "03/02/2024")
+	r = httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+	err = dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.ErrorContains(t, err,
+		`could not parse "03/02/2024" as time.Time using "2006-01-02T15:04:05Z07:00", "2006-01-02", epoch milliseconds`)
+}
+
+func TestTagFormatResolverMapTags(t *testing.T) {
+	type DataType struct {
+		Date time.Time `instruct:"query,layout=2006-01-02"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.Resolver = NewTagFormatResolver(opt.Resolver)
+	opt.StructInfoCache(true)
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	var data DataType
+	r := httptest.NewRequest(http.MethodGet, "/?date=2024-02-03", nil)
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+	require.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), data.Date)
+
+	// the tag of the cached struct info is overridden, so the resolve function must use the new layout.
+	decOpt := GetTestDecoderDecodeOptions(nil)
+	decOpt.MapTags = map[string]any{
+		"Date": "query,layout=02/01/2006",
+	}
+	r = httptest.NewRequest(http.MethodGet, "/?date=04/02/2024", nil)
+	require.NoError(t, dec.Decode(r, &data, decOpt))
+	require.Equal(t, time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC), data.Date)
+
+	r = httptest.NewRequest(http.MethodGet, "/?date=2024-02-05", nil)
+	require.NoError(t, dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil)))
+	require.Equal(t, time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC), data.Date)
+}
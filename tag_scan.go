@@ -0,0 +1,122 @@
+package instruct
+
+import (
+	"strings"
+)
+
+// tagOption is a "name=value" option of a tag.
+type tagOption struct {
+	raw      string // option text before unescaping, used in error messages.
+	name     string
+	value    string
+	hasValue bool // whether the option text contains an unescaped "=".
+}
+
+// splitTag splits the tag text into the operation and the options, unescaping and unquoting the values.
+// Blank options are skipped.
+func splitTag(s string) (string, []tagOption) {
+	operation, n := scanTagText(s, false)
+	s = s[n:]
+
+	var options []tagOption
+	for s != "" {
+		s = s[1:] // skip the comma
+		start := s
+
+		var opt tagOption
+		opt.name, n = scanTagText(s, true)
+		s = s[n:]
+		if strings.HasPrefix(s, "=") {
+			value, n := scanTagValue(s[1:])
+			opt.value, opt.hasValue = value, true
+			s = s[1+n:]
+		}
+		opt.raw = start[:len(start)-len(s)]
+		if opt.raw != "" {
+			options = append(options, opt)
+		}
+	}
+	return operation, options
+}
+
+// scanTagText scans unquoted text until an unescaped "," (or "=" if stopAtEquals is true). Returns the unescaped
+// text and the number of bytes read.
+func scanTagText(s string, stopAtEquals bool) (string, int) {
+	var b strings.Builder
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && isTagSpecial(s[i+1]) {
+			i++
+			b.WriteByte(s[i])
+			continue
+		}
+		if c == ',' || (stopAtEquals && c == '=') {
+			break
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), i
+}
+
+// scanTagValue scans an option value until the next unescaped comma. Values starting with "'" are quoted if
+// there is a closing "'" followed by a comma or the end of the text. Otherwise the "'" is part of the value, like
+// before quoting was supported, so tags like "example='x" keep working. Returns the unescaped value and the
+// number of bytes read.
+func scanTagValue(s string) (string, int) {
+	if value, n, ok := scanTagQuotedValue(s); ok {
+		return value, n
+	}
+	return scanTagText(s, false)
+}
+
+// scanTagQuotedValue scans a quoted value, returning false if s is not a terminated quoted value.
+func scanTagQuotedValue(s string) (string, int, bool) {
+	if !strings.HasPrefix(s, "'") {
+		return "", 0, false
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && (s[i+1] == '\'' || s[i+1] == '\\') {
+			i++
+			b.WriteByte(s[i])
+			continue
+		}
+		if c == '\'' {
+			if i+1 < len(s) && s[i+1] != ',' {
+				return "", 0, false
+			}
+			return b.String(), i + 1, true
+		}
+		b.WriteByte(c)
+	}
+	return "", 0, false
+}
+
+// isTagSpecial returns whether the character can be escaped with a backslash.
+func isTagSpecial(c byte) bool {
+	return c == ',' || c == '=' || c == '\'' || c == '\\'
+}
+
+// escapeTagText escapes the characters that would split an operation or option name.
+func escapeTagText(s string, isName bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == ',' || c == '\\' || (isName && c == '=') {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// quoteTagValue quotes the value if it contains characters that would be parsed differently.
+func quoteTagValue(s string) string {
+	if !strings.ContainsAny(s, ",\\") && !strings.HasPrefix(s, "'") {
+		return s
+	}
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(s) + "'"
+}
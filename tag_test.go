@@ -2,6 +2,9 @@ package instruct
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -9,6 +12,7 @@ import (
 
 func TestParseTag(t *testing.T) {
 	defOpt := GetTestDecoderOptions()
+	siOpt := defOpt.structInfoOptions()
 
 	tests := []struct {
 		name              string
@@ -18,6 +22,7 @@ func TestParseTag(t *testing.T) {
 		expectedName      string
 		expectedOperation string
 		expectedRequired  bool
+		expectedDefault   *string
 		expectedOptions   map[string]string
 		expectedSOWhen    string
 		expectedSORecurse bool
@@ -76,6 +81,28 @@ func TestParseTag(t *testing.T) {
 				"b": "2",
 			},
 		},
+		{
+			name:              "with default",
+			fieldName:         "Val",
+			tagValue:          "header,default=12,a=1",
+			expectedName:      "val",
+			expectedOperation: "header",
+			expectedRequired:  true,
+			expectedDefault:   ptrTo("12"),
+			expectedOptions: map[string]string{
+				"a": "1",
+			},
+		},
+		{
+			name:              "with empty default",
+			fieldName:         "Val",
+			tagValue:          "header,default=",
+			expectedName:      "val",
+			expectedOperation: "header",
+			expectedRequired:  true,
+			expectedDefault:   ptrTo(""),
+			expectedOptions:   map[string]string{},
+		},
 		{
 			name:          "invalid options",
 			fieldName:     "Val",
@@ -114,12 +141,69 @@ func TestParseTag(t *testing.T) {
 			tagValue:      "header,so_invalid=invalid_value",
 			expectedError: true,
 		},
+		{
+			name:              "quoted values",
+			fieldName:         "Val",
+			tagValue:          `query,default='a,b',explodesep=',',c='it\'s \\ ok'`,
+			expectedName:      "val",
+			expectedOperation: "query",
+			expectedRequired:  true,
+			expectedDefault:   ptrTo("a,b"),
+			expectedOptions: map[string]string{
+				"explodesep": ",",
+				"c":          `it's \ ok`,
+			},
+		},
+		{
+			name:              "escaped values",
+			fieldName:         "Val",
+			tagValue:          `query,name=a\,b,c=x\=y,d=1=2,e=it's,f=C:\dir`,
+			expectedName:      "a,b",
+			expectedOperation: "query",
+			expectedRequired:  true,
+			expectedOptions: map[string]string{
+				"c": "x=y",
+				"d": "1=2",
+				"e": "it's",
+				"f": `C:\dir`,
+			},
+		},
+		{
+			// values which are not terminated quoted values are literal, like before quoting was supported.
+			name:              "unterminated quoted value",
+			fieldName:         "Val",
+			tagValue:          "query,default=',example='x,c='a\\'",
+			expectedName:      "val",
+			expectedOperation: "query",
+			expectedRequired:  true,
+			expectedDefault:   ptrTo("'"),
+			expectedOptions: map[string]string{
+				"example": "'x",
+				"c":       "'a'",
+			},
+		},
+		{
+			name:              "characters after quoted value",
+			fieldName:         "Val",
+			tagValue:          "query,default='a'b",
+			expectedName:      "val",
+			expectedOperation: "query",
+			expectedRequired:  true,
+			expectedDefault:   ptrTo("'a'b"),
+			expectedOptions:   map[string]string{},
+		},
+		{
+			name:          "unterminated quoted value with comma",
+			fieldName:     "Val",
+			tagValue:      "query,default='a,b",
+			expectedError: true,
+		},
 	}
 
 	for i := range tests {
 		tt := tests[i]
 		t.Run(tt.name, func(t *testing.T) {
-			tag, err := parseTags[*http.Request, TestDecodeContext](tt.fieldName, tt.tagValue, &defOpt)
+			tag, err := parseTags(tt.fieldName, tt.tagValue, &siOpt)
 			if tt.expectedError {
 				require.Error(t, err)
 			} else {
@@ -127,6 +211,10 @@ func TestParseTag(t *testing.T) {
 				require.Equal(t, tt.expectedName, tag.Name)
 				require.Equal(t, tt.expectedOperation, tag.Operation)
 				require.Equal(t, tt.expectedRequired, tag.Required)
+				require.Equal(t, tt.expectedDefault != nil, tag.HasDefault)
+				if tt.expectedDefault != nil {
+					require.Equal(t, *tt.expectedDefault, tag.Default)
+				}
 				require.Equal(t, tt.expectedOptions, tag.Options.options)
 				require.Equal(t, tt.expectedSOWhen, tag.SOWhen)
 				require.Equal(t, tt.expectedSORecurse, tag.SORecurse)
@@ -135,3 +223,123 @@ func TestParseTag(t *testing.T) {
 	}
 
 }
+
+func TestTagString(t *testing.T) {
+	defOpt := GetTestDecoderOptions()
+	siOpt := defOpt.structInfoOptions()
+
+	tests := []struct {
+		tagValue string
+		expected string
+	}{
+		{
+			tagValue: "header",
+			expected: "header",
+		},
+		{
+			tagValue: "header,name=X-Val,required=true",
+			expected: "header,name=X-Val,required=true",
+		},
+		{
+			tagValue: "query,required=false,b=2,a=1,default=x",
+			expected: "query,required=false,default=x,a=1,b=2",
+		},
+		{
+			tagValue: "body,so_recurse=true,so_when=before",
+			expected: "body,so_when=before,so_recurse=true",
+		},
+		{
+			tagValue: "body,so_recurse=false",
+			expected: "body,so_recurse=false",
+		},
+		{
+			tagValue: `query,name=a\,b,default='1,2',explodesep=\,,c='it\'s',d=C:\dir,e=x=y`,
+			expected: `query,name='a,b',default='1,2',c=it's,d='C:\\dir',e=x=y,explodesep=','`,
+		},
+		{
+			tagValue: `query,c=\'x`,
+			expected: `query,c='\'x'`,
+		},
+		{
+			tagValue: `query,c='x,d=1`,
+			expected: `query,c='\'x',d=1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tagValue, func(t *testing.T) {
+			tag, err := parseTags("Val", tt.tagValue, &siOpt)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tag.String())
+
+			roundTrip, err := parseTags("Val", tag.String(), &siOpt)
+			require.NoError(t, err)
+			require.Equal(t, tag, roundTrip)
+		})
+	}
+
+	// tags not created by parsing write the name and required options.
+	tag := &Tag{Operation: "query", Name: "val", Options: NewTagOptions()}
+	require.Equal(t, "query,name=val,required=false", tag.String())
+}
+
+func TestTagStringNameFromTags(t *testing.T) {
+	defOpt := GetTestDecoderOptions()
+	defOpt.NameFromTags = []string{"json"}
+	siOpt := defOpt.structInfoOptions()
+
+	field, ok := reflect.TypeOf(struct {
+		A string `instruct:"query" json:"user_id"`
+	}{}).FieldByName("A")
+	require.True(t, ok)
+
+	tag, err := parseStructTagStructField(&buildContext{}, field, level{}, &siOpt)
+	require.NoError(t, err)
+	require.Equal(t, "user_id", tag.Name)
+	require.Equal(t, "query", tag.String())
+}
+
+func TestDecodeQuotedTagValues(t *testing.T) {
+	type DataType struct {
+		A []string `instruct:"query,default='x,y'"`
+		B []string `instruct:"query,explode=true,explodesep='|,'"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?b="+url.QueryEscape("1|,2"), nil)
+
+	dec := NewDecoder[*http.Request, TestDecodeContext](GetTestDecoderOptions())
+
+	var data DataType
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"x", "y"}, data.A)
+	require.Equal(t, []string{"1", "2"}, data.B)
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestDecodeNameFromTags(t *testing.T) {
+	type DataType struct {
+		A string `instruct:"query" json:"user_id,omitempty"`
+		B string `instruct:"query,name=explicit" json:"ignored"`
+		C string `instruct:"query" json:",omitempty" form:"from_form"`
+		D string `instruct:"query" json:"-"`
+		E string `json:"from_map_tags"`
+	}
+
+	opt := GetTestDecoderOptions()
+	opt.NameFromTags = []string{"json", "form"}
+	opt.DefaultMapTagsSet(reflect.TypeOf(DataType{}), MapTags{
+		"E": "query",
+	})
+	dec := NewDecoder[*http.Request, TestDecodeContext](opt)
+
+	r := httptest.NewRequest(http.MethodGet, "/?user_id=1&explicit=2&from_form=3&d=4&from_map_tags=5", nil)
+
+	var data DataType
+	err := dec.Decode(r, &data, GetTestDecoderDecodeOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, DataType{A: "1", B: "2", C: "3", D: "4", E: "5"}, data)
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/rrgmc/instruct/coerce"
 )
@@ -12,6 +13,8 @@ var (
 	ErrCoerceOverflow    = coerce.ErrOverflow
 	ErrCoerceUnsupported = coerce.ErrUnsupported
 	ErrCoerceUnknown     = fmt.Errorf("coerce: unknown type")
+	ErrUnknownOperation  = fmt.Errorf("unknown operation")
+	ErrListMaxLength     = fmt.Errorf("list maximum length exceeded")
 )
 
 // An ValuesNotUsedError is returned when some values were not used.
@@ -84,3 +87,48 @@ func (c CoerceError) Error() string {
 func (c CoerceError) Unwrap() error {
 	return c.err
 }
+
+// A FieldError is returned when decoding a field fails. Err contains the actual error, and can be
+// checked with [errors.As] and [errors.Is].
+type FieldError struct {
+	FieldPath []string // complete field path, using the struct field names. Empty for the root struct option.
+	Operation string
+	TagName   string
+	Value     any // raw value returned by the operation, if available.
+	Err       error
+}
+
+func (e *FieldError) Error() string {
+	if len(e.FieldPath) == 0 {
+		return fmt.Sprintf("error decoding struct option (tag name '%s') with operation '%s': %s",
+			e.TagName, e.Operation, e.Err)
+	}
+	return fmt.Sprintf("error decoding field '%s' (tag name '%s') with operation '%s': %s",
+		strings.Join(e.FieldPath, "."), e.TagName, e.Operation, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is returned when error aggregation is enabled and one or more fields failed decoding.
+type DecodeErrors []*FieldError
+
+func (e DecodeErrors) Error() string {
+	var b strings.Builder
+	for i, err := range e {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e DecodeErrors) Unwrap() []error {
+	ret := make([]error, len(e))
+	for i, err := range e {
+		ret[i] = err
+	}
+	return ret
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TagOptionType is the type of the value of a tag option.
+type TagOptionType int
+
+const (
+	TagOptionString TagOptionType = iota // any string.
+	TagOptionBool                        // a boolean parsed by [strconv.ParseBool].
+	TagOptionInt                         // an integer parsed by [strconv.Atoi].
+	TagOptionEnum                        // one of the values in TagOptionSpec.Values.
+)
+
+func (t TagOptionType) String() string {
+	switch t {
+	case TagOptionString:
+		return "string"
+	case TagOptionBool:
+		return "bool"
+	case TagOptionInt:
+		return "int"
+	case TagOptionEnum:
+		return "enum"
+	default:
+		return fmt.Sprintf("TagOptionType(%d)", int(t))
+	}
+}
+
+func (t TagOptionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// TagOptionSpec describes a tag option accepted by an operation or resolver.
+type TagOptionSpec struct {
+	Name        string        `json:"name"`
+	Type        TagOptionType `json:"type"`
+	Values      []string      `json:"values,omitempty"`      // valid values for TagOptionEnum.
+	Default     string        `json:"default,omitempty"`     // documented default, the operation applies its own.
+	Description string        `json:"description,omitempty"` // description used for documentation.
+}
+
+// Validate checks whether the value is valid for the option type.
+func (s TagOptionSpec) Validate(value string) error {
+	switch s.Type {
+	case TagOptionBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid boolean value '%s' for option '%s'", value, s.Name)
+		}
+	case TagOptionInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("invalid integer value '%s' for option '%s'", value, s.Name)
+		}
+	case TagOptionEnum:
+		for _, v := range s.Values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value '%s' for option '%s', must be one of: %s", value, s.Name,
+			strings.Join(s.Values, ", "))
+	}
+	return nil
+}
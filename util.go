@@ -23,16 +23,10 @@ func reflectValueElem(v reflect.Value) reflect.Value {
 
 // reflectEnsurePointerValue ensures that a pointer value is initialized recursively.
 func reflectEnsurePointerValue(v *reflect.Value) {
-	for v.Kind() == reflect.Ptr {
-		var newv reflect.Value
-		if v.IsNil() {
-			newv = reflect.New(v.Type().Elem())
-			v.Set(newv)
-			newv = newv.Elem()
-		} else {
-			newv = v.Elem()
+	for cur := *v; cur.Kind() == reflect.Ptr; cur = cur.Elem() {
+		if cur.IsNil() {
+			cur.Set(reflect.New(cur.Type().Elem()))
 		}
-		v = &newv
 	}
 }
 